@@ -0,0 +1,104 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"github.com/dembygenesis/local.tools/internal/utils_common"
+	"github.com/spf13/cobra"
+	"io"
+	"os"
+	"strings"
+)
+
+var applyClipboardCmd = &cobra.Command{
+	Use:   applyClipboard.string() + " [root]",
+	Short: "Writes the files of a bundle on the clipboard (or stdin) to disk, after a diff review.",
+	Long: `Parses a multi-file bundle, as produced by clip-file-contents or as it comes back from a chat,
+and writes its files under the root (the working directory by default).
+
+Every format of clip-file-contents is understood ('--- path ---' headers, markdown, xml and json), along
+with fenced code blocks whose path is given by the line above them ("### path", "**path**", "File: path"),
+or in their info string ("` + "```go:path" + `"). Prose around the files is ignored.
+
+The diff of every file against the working tree is printed first. Nothing is written until confirmed
+(or --yes is set). A bundle with a path escaping the root is refused as a whole.`,
+	Args:          cobra.MaximumNArgs(1),
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		stdin, _ := cmd.Flags().GetBool("stdin")
+		yes, _ := cmd.Flags().GetBool("yes")
+
+		opts := utils_common.ApplyOptions{
+			Root:  ".",
+			Stdin: stdin,
+		}
+		if len(args) > 0 {
+			opts.Root = args[0]
+		}
+
+		plan, err := srv.PlanApplyBundle(&opts, cmd.InOrStdin())
+		if err != nil {
+			return err
+		}
+
+		pending := plan.Pending()
+		for _, change := range plan.Changes {
+			logger.Infof("%s (%s)", change.Path, change.Status)
+		}
+		for _, change := range pending {
+			fmt.Print(change.Diff)
+		}
+		if len(pending) == 0 {
+			logger.Info("Nothing to apply, the files are up to date")
+			return nil
+		}
+
+		if !yes {
+			ok, err := confirm(fmt.Sprintf("Write %d file(s) under '%s'? [y/N] ", len(pending), plan.Root), stdin)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				logger.Warn("Aborted, nothing was written")
+				return nil
+			}
+		}
+
+		if err := srv.ApplyBundle(plan); err != nil {
+			return err
+		}
+		logger.Infof("Wrote \033[1;34m%d\033[0m file(s) under '%s'", len(pending), plan.Root)
+
+		return nil
+	},
+}
+
+// confirm asks a yes/no question, answered on stdin or on the terminal
+// when stdin already carries the input.
+func confirm(question string, stdinUsed bool) (bool, error) {
+	var in io.Reader = os.Stdin
+	if stdinUsed {
+		tty, err := os.Open("/dev/tty")
+		if err != nil {
+			return false, errors.New("cannot ask for confirmation without a terminal, use --yes")
+		}
+		defer tty.Close()
+		in = tty
+	}
+
+	fmt.Fprint(os.Stderr, question)
+	answer, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && answer == "" {
+		return false, nil
+	}
+
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes", nil
+}
+
+func init() {
+	applyClipboardCmd.Flags().Bool("stdin", false, "Reads the bundle from stdin instead of the clipboard")
+	applyClipboardCmd.Flags().BoolP("yes", "y", false, "Writes the files without asking for confirmation")
+}
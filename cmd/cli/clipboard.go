@@ -1,20 +1,245 @@
 package main
 
 import (
+	"fmt"
+	"github.com/dembygenesis/local.tools/internal/utils_common"
 	"github.com/spf13/cobra"
+	"strings"
 )
 
 var copyToClipboardCmd = &cobra.Command{
-	Use:   clipFileContents.string(),
-	Short: "Copy to clipboard copies from the directory provided.",
-	Long:  `Copies files contents from the root path provided.`,
-	Args:  cobra.ExactArgs(1),
+	Use:   clipFileContents.string() + " [path]...",
+	Short: "Copy to clipboard copies from the files and directories provided.",
+	Long: `Copies files contents from any mix of files and directories provided, e.g:
+
+    clip-file-contents cmd/cli/root.go internal/cli di/cfg/services.go
+
+Files are de-duplicated and named relative to the common base directory of the arguments.
+
+The contents go to the clipboard by default. --stdout and --out <file> send them to stdout or a file
+instead, for headless machines, containers, SSH sessions and piping into other tools.
+
+Secrets (private keys, known token formats, credentials assigned in code or configuration
+and high entropy strings) are masked as [REDACTED] before the contents leave the machine,
+unless --no-redact is set.
+
+--profile <name> clips a profile of the configuration (CLIP_PROFILES or CLIP_PROFILES_FILE),
+its root being used when no path is given, e.g:
+
+    clip-file-contents --profile db
+
+--archive <file.zip|file.tar.gz> packages the same selection of files (with a manifest of their paths
+and sizes) into an archive to upload instead.
+
+--split-by dir|package|size:<bytes> --out-dir <dir> writes one bundle file per directory, Go package or
+size budget, each opened by a header and its tree, along with an INDEX.md, for tools limiting the size of
+uploaded files.
+
+--go-package <dir> clips the files of a Go package. With --deps, it also clips the packages of the same
+module it imports, transitively (offline, from the import clauses alone), dependencies first, e.g:
+
+    clip-file-contents --go-package ./internal/persistence/helpers/mysql/connection --deps --no-tests`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		profile, _ := cmd.Flags().GetString("profile")
+		goPackages, _ := cmd.Flags().GetStringArray("go-package")
+		if profile != "" || len(goPackages) > 0 {
+			return nil
+		}
+		return cobra.MinimumNArgs(1)(cmd, args)
+	},
 	Run: func(cmd *cobra.Command, args []string) {
-		files, err := srv.CopyToClipboard(args[0], nil)
+		binary, _ := cmd.Flags().GetString("binary")
+		maxTokens, _ := cmd.Flags().GetInt("max-tokens")
+		maxFileBytes, _ := cmd.Flags().GetInt64("max-file-bytes")
+		maxTotalBytes, _ := cmd.Flags().GetInt64("max-total-bytes")
+		format, _ := cmd.Flags().GetString("format")
+		stdout, _ := cmd.Flags().GetBool("stdout")
+		outFile, _ := cmd.Flags().GetString("out")
+		include, _ := cmd.Flags().GetStringArray("include")
+		exclude, _ := cmd.Flags().GetStringArray("exclude")
+		forceInclude, _ := cmd.Flags().GetBool("force-include")
+		tree, _ := cmd.Flags().GetBool("tree")
+		treeOnly, _ := cmd.Flags().GetBool("tree-only")
+		changed, _ := cmd.Flags().GetBool("changed")
+		staged, _ := cmd.Flags().GetBool("staged")
+		since, _ := cmd.Flags().GetString("since")
+		noRedact, _ := cmd.Flags().GetBool("no-redact")
+		lineNumbers, _ := cmd.Flags().GetBool("line-numbers")
+		outline, _ := cmd.Flags().GetBool("outline")
+		minify, _ := cmd.Flags().GetBool("minify")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		sortBy, _ := cmd.Flags().GetString("sort")
+		symlinks, _ := cmd.Flags().GetString("symlinks")
+		profile, _ := cmd.Flags().GetString("profile")
+		goPackages, _ := cmd.Flags().GetStringArray("go-package")
+		deps, _ := cmd.Flags().GetBool("deps")
+		depsDepth, _ := cmd.Flags().GetInt("deps-depth")
+		noTests, _ := cmd.Flags().GetBool("no-tests")
+		archive, _ := cmd.Flags().GetString("archive")
+		splitBy, _ := cmd.Flags().GetString("split-by")
+		outDir, _ := cmd.Flags().GetString("out-dir")
+
+		opts := utils_common.ClipOptions{
+			Paths:         args,
+			Profile:       profile,
+			Include:       include,
+			Exclude:       exclude,
+			ForceInclude:  forceInclude,
+			Binary:        binary,
+			MaxTokens:     maxTokens,
+			MaxFileBytes:  maxFileBytes,
+			MaxTotalBytes: maxTotalBytes,
+			Format:        format,
+			Stdout:        stdout,
+			OutFile:       outFile,
+			Tree:          tree,
+			TreeOnly:      treeOnly,
+			GitChanged:    changed,
+			GitStaged:     staged,
+			GitSince:      since,
+			NoRedact:      noRedact,
+			LineNumbers:   lineNumbers,
+			Outline:       outline,
+			Minify:        minify,
+			DryRun:        dryRun,
+			Sort:          sortBy,
+			Symlinks:      symlinks,
+			GoPackages:    goPackages,
+			Deps:          deps,
+			DepsDepth:     depsDepth,
+			NoTests:       noTests,
+			Archive:       archive,
+			SplitBy:       splitBy,
+			OutDir:        outDir,
+		}
+
+		if opts.Archive != "" {
+			result, err := srv.ArchiveFiles(cmd.Context(), &opts)
+			if err != nil {
+				logger.Errorf("archive file contents: %v", err)
+				return
+			}
+			reportArchive(result)
+			return
+		}
+
+		result, err := srv.ClipFileContents(cmd.Context(), &opts)
 		if err != nil {
-			logger.Errorf("copy to clipboard: %v", err)
+			logger.Errorf("clip file contents: %v", err)
 			return
 		}
-		logger.Infof("copied \033[1;34m%v\033[0m files to clipboard!", len(files))
+
+		reportClip(result, &opts)
 	},
 }
+
+// reportClip prints the dry run listing or logs where the contents went,
+// along with what was skipped, redacted, minified or truncated on the way.
+func reportClip(result *utils_common.ClipResult, opts *utils_common.ClipOptions) {
+	if opts.DryRun {
+		fmt.Print(utils_common.FormatDryRun(result, opts.Sort))
+	} else {
+		destination := "clipboard"
+		switch {
+		case opts.Stdout:
+			destination = "stdout"
+		case opts.OutFile != "":
+			destination = opts.OutFile
+		case opts.OutDir != "":
+			destination = opts.OutDir
+		}
+		logger.Infof("copied \033[1;34m%v\033[0m files (~%v tokens) to %s in %v part(s)!", len(result.Files), result.Tokens, destination, result.Chunks)
+
+		if len(result.Skipped) > 0 {
+			logger.Warnf("skipped the contents of \033[1;33m%v\033[0m files:", len(result.Skipped))
+			for _, skipped := range result.Skipped {
+				logger.Warnf("  %s %s", skipped.Path, skipped.Reason)
+			}
+		}
+	}
+
+	if len(result.Redacted) > 0 {
+		logger.Warnf("redacted \033[1;33m%v\033[0m secrets (use --no-redact to keep them):", len(result.Redacted))
+		for _, redacted := range result.Redacted {
+			logger.Warnf("  %s:%d %s", redacted.Path, redacted.Line, redacted.Kind)
+		}
+	}
+
+	if len(result.Minified) > 0 {
+		var saved int64
+		for _, minified := range result.Minified {
+			saved += minified.Saved
+		}
+		logger.Infof("minified \033[1;34m%v\033[0m files, saving %s:", len(result.Minified), utils_common.FormatBytes(saved))
+		for _, minified := range result.Minified {
+			logger.Infof("  %s (-%s)", minified.Path, utils_common.FormatBytes(minified.Saved))
+		}
+	}
+
+	if len(result.Truncated) > 0 {
+		logger.Warnf("truncated \033[1;33m%v\033[0m files to fit the size limits:", len(result.Truncated))
+		for _, truncated := range result.Truncated {
+			logger.Warnf("  %s (kept %s of %s)", truncated.Path, utils_common.FormatBytes(truncated.Kept), utils_common.FormatBytes(truncated.Size))
+		}
+	}
+
+	if len(result.Largest) > 0 {
+		largest := make([]string, 0, len(result.Largest))
+		for _, contribution := range result.Largest {
+			largest = append(largest, fmt.Sprintf("%s (%s)", contribution.Path, utils_common.FormatBytes(contribution.Bytes)))
+		}
+		logger.Infof("largest files: %s", strings.Join(largest, ", "))
+	}
+}
+
+// reportArchive logs what was packaged into the archive.
+func reportArchive(result *utils_common.ArchiveResult) {
+	var total int64
+	for _, file := range result.Files {
+		total += file.Size
+	}
+	logger.Infof("archived \033[1;34m%v\033[0m files (%s) from '%s' to %s (%s)", len(result.Files), utils_common.FormatBytes(total), result.Base, result.Path, utils_common.FormatBytes(result.Bytes))
+
+	if len(result.Redacted) > 0 {
+		logger.Warnf("redacted \033[1;33m%v\033[0m secrets (use --no-redact to keep them):", len(result.Redacted))
+		for _, redacted := range result.Redacted {
+			logger.Warnf("  %s:%d %s", redacted.Path, redacted.Line, redacted.Kind)
+		}
+	}
+}
+
+func init() {
+	copyToClipboardCmd.Flags().StringArray("include", nil, "Only clips files matching this glob, relative to the root (repeatable, e.g '**/*.go')")
+	copyToClipboardCmd.Flags().StringArray("exclude", nil, "Skips files and folders matching this glob, relative to the root (repeatable, e.g 'internal/**/fakes/**')")
+	copyToClipboardCmd.Flags().Bool("force-include", false, "Lets --include globs override the default exclusions and ignore files")
+	copyToClipboardCmd.Flags().String("binary", utils_common.BinaryPlaceholder, "How binary files are handled: 'placeholder' or 'skip'")
+	copyToClipboardCmd.Flags().String("format", "", "Output format: 'plain', 'markdown', 'xml' or 'json' (defaults to CLIP_FORMAT or 'plain')")
+	copyToClipboardCmd.Flags().Int("max-tokens", 0, "Splits the contents into parts of at most this many (estimated) tokens, copied one by one")
+	copyToClipboardCmd.Flags().Int64("max-file-bytes", 0, "Truncates files over this many bytes, keeping their head and tail")
+	copyToClipboardCmd.Flags().Int64("max-total-bytes", 0, "Truncates the largest files until all of them fit in this many bytes")
+	copyToClipboardCmd.Flags().Bool("stdout", false, "Writes the contents to stdout instead of the clipboard")
+	copyToClipboardCmd.Flags().String("out", "", "Writes the contents to this file instead of the clipboard")
+	copyToClipboardCmd.Flags().Bool("tree", false, "Prepends an ASCII tree of the clipped files, with their sizes")
+	copyToClipboardCmd.Flags().Bool("tree-only", false, "Clips the ASCII tree of the selected files, without their contents")
+	copyToClipboardCmd.Flags().Bool("changed", false, "Only clips files with uncommitted changes (staged, unstaged and untracked)")
+	copyToClipboardCmd.Flags().Bool("staged", false, "Only clips files with staged changes")
+	copyToClipboardCmd.Flags().String("since", "", "Only clips files changed since the branch forked from this branch, tag or commit (its merge base) and the uncommitted ones")
+	copyToClipboardCmd.Flags().Bool("line-numbers", false, "Prefixes every line with its right-aligned number, e.g '  7 | func main() {'")
+	copyToClipboardCmd.Flags().Bool("outline", false, "Clips only the API surface of Go files (declarations, signatures and doc comments), eliding function bodies")
+	copyToClipboardCmd.Flags().Bool("minify", false, "Strips comments and collapses blank lines in Go, SQL, shell, YAML, JSON and JS/TS files")
+	copyToClipboardCmd.Flags().Bool("no-redact", false, "Keeps secrets (keys, tokens, passwords) in the contents instead of masking them")
+	copyToClipboardCmd.Flags().Bool("dry-run", false, "Lists the files that would be clipped, with their size, lines and tokens as a markdown table, without clipping them")
+	copyToClipboardCmd.Flags().String("sort", utils_common.SortByPath, "How --dry-run sorts the files: 'path', 'size' or 'tokens'")
+	copyToClipboardCmd.Flags().String("symlinks", utils_common.SymlinkFollow, "How symbolic links are handled: 'skip', 'follow' (clipping their targets) or 'preserve' (clipping a placeholder naming the target)")
+	copyToClipboardCmd.Flags().StringArray("go-package", nil, "Clips the Go files of this package directory or import path of the module (repeatable)")
+	copyToClipboardCmd.Flags().Bool("deps", false, "Adds the packages of the module that the --go-package packages import, transitively, in dependency order")
+	copyToClipboardCmd.Flags().Int("deps-depth", 0, "Limits --deps to this many levels of imports (0 being no limit)")
+	copyToClipboardCmd.Flags().Bool("no-tests", false, "Leaves the _test.go files of the --go-package packages out")
+	copyToClipboardCmd.Flags().String("archive", "", "Packages the selected files and a manifest into this .zip or .tar.gz file instead of clipping their contents")
+	copyToClipboardCmd.Flags().String("split-by", "", "Writes one bundle per 'dir', Go 'package' or 'size:<bytes>' (e.g 'size:500k') into --out-dir, with an index")
+	copyToClipboardCmd.Flags().String("out-dir", "", "Directory the --split-by bundles and their INDEX.md are written to")
+	copyToClipboardCmd.Flags().String("profile", "", "Clips the named profile of CLIP_PROFILES or CLIP_PROFILES_FILE (its root, globs, format and token budget)")
+	copyToClipboardCmd.MarkFlagsMutuallyExclusive("stdout", "out")
+	copyToClipboardCmd.MarkFlagsMutuallyExclusive("dry-run", "stdout", "out")
+	copyToClipboardCmd.MarkFlagsMutuallyExclusive("changed", "staged", "since")
+}
@@ -11,6 +11,10 @@ const (
 	clipGptPreface   command = "clip-gpt-preface"
 	clipFileContents command = "clip-file-contents"
 	copyFolderAToB   command = "copy-folder-a-to-b"
+	history          command = "history"
+	applyClipboard   command = "apply-clipboard"
+	applyPatch       command = "apply-patch"
+	clipSymbol       command = "clip-symbol"
 )
 
 func (c command) string() string {
@@ -23,10 +23,12 @@ var copyFolderAToBCommand = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		src := args[0]
 		dst := args[1]
+		symlinks, _ := cmd.Flags().GetString("symlinks")
 
 		opts := utils_common.CopyOptions{
 			Source:      args[0],
 			Destination: args[1],
+			Symlinks:    symlinks,
 		}
 
 		if err := srv.CopyDirToAnother(&opts); err != nil {
@@ -37,3 +39,7 @@ var copyFolderAToBCommand = &cobra.Command{
 		logger.Infof("Copied '\033[1m%s\033[0m' to '\033[1m%s\033[0m'", src, dst)
 	},
 }
+
+func init() {
+	copyFolderAToBCommand.Flags().String("symlinks", utils_common.SymlinkFollow, "How symbolic links are handled: 'skip', 'follow' (copying their targets) or 'preserve' (recreating the links)")
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"fmt"
+	"github.com/dembygenesis/local.tools/internal/lib/doc_generator"
+	"github.com/dembygenesis/local.tools/internal/utils_common"
+	"github.com/spf13/cobra"
+	"strconv"
+	"strings"
+)
+
+// historyArgsWidth is the number of characters of a clip's arguments listed.
+const historyArgsWidth = 60
+
+var historyCmd = &cobra.Command{
+	Use:   history.string(),
+	Short: "Lists, shows and restores past clipboard writes.",
+	Long: `Every clipboard write (clip-file-contents, clip-gpt-preface, ...) is recorded in a local history,
+with its time, command, arguments, size and content hash.
+
+The history lives under the user's data directory (e.g ~/.local/share/overwatch/history) or CLIP_HISTORY_DIR.
+It keeps the latest CLIP_HISTORY_MAX_ENTRIES clips (200 by default), that are at most CLIP_HISTORY_MAX_AGE
+old (e.g 720h, 30 days by default).`,
+}
+
+var historyListCmd = &cobra.Command{
+	Use:           "list",
+	Short:         "Lists the recorded clips, newest first.",
+	Args:          cobra.NoArgs,
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		entries, err := srv.ListHistory()
+		if err != nil {
+			return err
+		}
+		if len(entries) == 0 {
+			logger.Info("The clip history is empty")
+			return nil
+		}
+
+		table := [][]string{{"ID", "Time", "Command", "Args", "Size", "Hash"}}
+		for _, entry := range entries {
+			table = append(table, []string{
+				strconv.Itoa(entry.ID),
+				entry.Time.Local().Format("2006-01-02 15:04:05"),
+				entry.Command,
+				shorten(strings.Join(entry.Args, " "), historyArgsWidth),
+				utils_common.FormatBytes(entry.Size),
+				entry.Hash[:min(12, len(entry.Hash))],
+			})
+		}
+		fmt.Println(doc_generator.FormatAsMDTable(table))
+
+		return nil
+	},
+}
+
+var historyShowCmd = &cobra.Command{
+	Use:           "show <id>",
+	Short:         "Prints the content of a recorded clip to stdout.",
+	Args:          cobra.ExactArgs(1),
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id, err := strconv.Atoi(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid id '%s': %v", args[0], err)
+		}
+
+		entry, content, err := srv.ShowHistory(id)
+		if err != nil {
+			return err
+		}
+
+		logger.Infof("Clip \033[1;34m%d\033[0m, %s of '%s' at %s", entry.ID, utils_common.FormatBytes(entry.Size), entry.Command, entry.Time.Local().Format("2006-01-02 15:04:05"))
+		fmt.Print(content)
+
+		return nil
+	},
+}
+
+var historyRestoreCmd = &cobra.Command{
+	Use:           "restore <id>",
+	Short:         "Puts a recorded clip back onto the clipboard.",
+	Args:          cobra.ExactArgs(1),
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id, err := strconv.Atoi(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid id '%s': %v", args[0], err)
+		}
+
+		entry, err := srv.RestoreHistory(id)
+		if err != nil {
+			return err
+		}
+
+		logger.Infof("Restored clip \033[1;34m%d\033[0m (%s of '%s') to the clipboard", entry.ID, utils_common.FormatBytes(entry.Size), entry.Command)
+
+		return nil
+	},
+}
+
+// shorten cuts s down to width characters, marking the cut with an ellipsis.
+func shorten(s string, width int) string {
+	runes := []rune(s)
+	if len(runes) <= width {
+		return s
+	}
+	return string(runes[:width-1]) + "…"
+}
+
+func init() {
+	historyCmd.AddCommand(historyListCmd)
+	historyCmd.AddCommand(historyShowCmd)
+	historyCmd.AddCommand(historyRestoreCmd)
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"fmt"
+	"github.com/dembygenesis/local.tools/internal/utils_common"
+	"github.com/spf13/cobra"
+	"strings"
+)
+
+var applyPatchCmd = &cobra.Command{
+	Use:   applyPatch.string() + " [root]",
+	Short: "Applies a unified diff on the clipboard (or stdin) to the files under the root.",
+	Long: `Applies a unified diff (git diff or diff -u), as models often answer with, to the files under the root
+(the working directory by default). Prose and code fences around the diff are ignored.
+
+Hunks are matched fuzzily: they are looked for around the line they claim, tolerating any drift, then
+ignoring whitespace differences, then ignoring up to 2 context lines at their ends. The outcome of every
+hunk is reported. The hunks that fail are written to a '<file>.rej' file next to the file.
+
+--check only reports whether the diff applies, without writing anything. The command fails when
+a hunk does not apply. A diff with a path escaping the root is refused as a whole.`,
+	Args:          cobra.MaximumNArgs(1),
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		stdin, _ := cmd.Flags().GetBool("stdin")
+		check, _ := cmd.Flags().GetBool("check")
+
+		opts := utils_common.PatchOptions{
+			Root:  ".",
+			Stdin: stdin,
+			Check: check,
+		}
+		if len(args) > 0 {
+			opts.Root = args[0]
+		}
+
+		result, err := srv.ApplyPatch(&opts, cmd.InOrStdin())
+		if err != nil {
+			return err
+		}
+
+		for _, file := range result.Files {
+			logger.Infof("%s (%s)", file.Path, file.Status)
+			for i, hunk := range file.Hunks {
+				if hunk.Applied {
+					logger.Infof("  hunk #%d applied at line %d%s", i+1, hunk.Line, hunkNotes(hunk))
+				} else {
+					logger.Warnf("  hunk #%d \033[1;33mFAILED\033[0m", i+1)
+				}
+			}
+			if file.Rejected != "" && !opts.Check {
+				logger.Warnf("  rejected hunks saved to %s", file.Rejected)
+			}
+		}
+
+		if failed := result.Failed(); failed > 0 {
+			return fmt.Errorf("%d hunk(s) did not apply", failed)
+		}
+		if opts.Check {
+			logger.Info("The patch applies cleanly, nothing was written (--check)")
+		} else {
+			logger.Infof("Patched \033[1;34m%d\033[0m file(s) under '%s'", len(result.Files), result.Root)
+		}
+
+		return nil
+	},
+}
+
+// hunkNotes describes how loosely a hunk matched, e.g " (offset +3, fuzz 1)".
+func hunkNotes(hunk utils_common.HunkResult) string {
+	var notes []string
+	if hunk.Offset != 0 {
+		notes = append(notes, fmt.Sprintf("offset %+d", hunk.Offset))
+	}
+	if hunk.Fuzz > 0 {
+		notes = append(notes, fmt.Sprintf("fuzz %d", hunk.Fuzz))
+	}
+	if hunk.Whitespace {
+		notes = append(notes, "whitespace ignored")
+	}
+	if len(notes) == 0 {
+		return ""
+	}
+	return " (" + strings.Join(notes, ", ") + ")"
+}
+
+func init() {
+	applyPatchCmd.Flags().Bool("stdin", false, "Reads the diff from stdin instead of the clipboard")
+	applyPatchCmd.Flags().Bool("check", false, "Only reports whether the diff applies, without writing anything")
+}
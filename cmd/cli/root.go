@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"github.com/dembygenesis/local.tools/di/ctn/dic"
 	"github.com/dembygenesis/local.tools/internal/cli"
@@ -9,6 +10,8 @@ import (
 	"github.com/spf13/cobra"
 	"log"
 	"os"
+	"os/signal"
+	"syscall"
 )
 
 var (
@@ -45,10 +48,19 @@ func init() {
 	rootCmd.AddCommand(copyToClipboardCmd)
 	rootCmd.AddCommand(copyGptCodePrefaceToClipboardCommand)
 	rootCmd.AddCommand(copyFolderAToBCommand)
+	rootCmd.AddCommand(historyCmd)
+	rootCmd.AddCommand(applyClipboardCmd)
+	rootCmd.AddCommand(applyPatchCmd)
+	rootCmd.AddCommand(clipSymbolCmd)
 }
 
 func main() {
-	if err := rootCmd.Execute(); err != nil {
+	// Ctrl-C cancels the context, so long running commands stop promptly.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := rootCmd.ExecuteContext(ctx); err != nil {
+		stop()
 		_, _ = fmt.Fprintf(os.Stderr, "ERROR: %+v\n", err)
 		os.Exit(1)
 	}
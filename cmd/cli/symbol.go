@@ -0,0 +1,78 @@
+package main
+
+import (
+	"github.com/dembygenesis/local.tools/internal/utils_common"
+	"github.com/spf13/cobra"
+)
+
+var clipSymbolCmd = &cobra.Command{
+	Use:   clipSymbol.string() + " <name> [path]...",
+	Short: "Clips a Go symbol's declaration, the types it uses and its call sites.",
+	Long: `Finds a Go function, method, type, constant or variable in the Go files under the paths (the
+working directory by default) and clips a context pack for a question about it, e.g:
+
+    clip-symbol Paginate
+    clip-symbol Service.CopyDirToAnother
+    clip-symbol utils_common.CopyOptions internal
+
+The pack holds the symbol's declaration (with its doc comment), the declarations of the module's types
+it uses and every call site referencing it with --context lines around them, each named after its file
+and lines (e.g 'internal/cli/service.go:35-60'). References are matched by name, without type checking.
+
+The contents are bundled like clip-file-contents does: the same formats, token budget, redaction
+and destinations apply.`,
+	Args:          cobra.MinimumNArgs(1),
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		symbolContext, _ := cmd.Flags().GetInt("context")
+		maxTokens, _ := cmd.Flags().GetInt("max-tokens")
+		format, _ := cmd.Flags().GetString("format")
+		stdout, _ := cmd.Flags().GetBool("stdout")
+		outFile, _ := cmd.Flags().GetString("out")
+		include, _ := cmd.Flags().GetStringArray("include")
+		exclude, _ := cmd.Flags().GetStringArray("exclude")
+		noRedact, _ := cmd.Flags().GetBool("no-redact")
+		lineNumbers, _ := cmd.Flags().GetBool("line-numbers")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+		opts := utils_common.ClipOptions{
+			Paths:         []string{"."},
+			Symbol:        args[0],
+			SymbolContext: symbolContext,
+			Include:       include,
+			Exclude:       exclude,
+			MaxTokens:     maxTokens,
+			Format:        format,
+			Stdout:        stdout,
+			OutFile:       outFile,
+			NoRedact:      noRedact,
+			LineNumbers:   lineNumbers,
+			DryRun:        dryRun,
+		}
+		if len(args) > 1 {
+			opts.Paths = args[1:]
+		}
+
+		result, err := srv.ClipFileContents(cmd.Context(), &opts)
+		if err != nil {
+			return err
+		}
+
+		reportClip(result, &opts)
+		return nil
+	},
+}
+
+func init() {
+	clipSymbolCmd.Flags().Int("context", utils_common.DefaultSymbolContext, "Lines clipped before and after every call site")
+	clipSymbolCmd.Flags().StringArray("include", nil, "Only searches files matching this glob, relative to the root (repeatable, e.g 'internal/**')")
+	clipSymbolCmd.Flags().StringArray("exclude", nil, "Skips files and folders matching this glob, relative to the root (repeatable, e.g '**/*_test.go')")
+	clipSymbolCmd.Flags().Int("max-tokens", 0, "Splits the contents into parts of at most this many (estimated) tokens, copied one by one")
+	clipSymbolCmd.Flags().String("format", "", "Output format: 'plain', 'markdown', 'xml' or 'json' (defaults to CLIP_FORMAT or 'plain')")
+	clipSymbolCmd.Flags().Bool("stdout", false, "Writes the contents to stdout instead of the clipboard")
+	clipSymbolCmd.Flags().String("out", "", "Writes the contents to this file instead of the clipboard")
+	clipSymbolCmd.Flags().Bool("no-redact", false, "Keeps secrets (keys, tokens, passwords) in the contents instead of masking them")
+	clipSymbolCmd.Flags().Bool("line-numbers", false, "Prefixes every line with its number in the file, e.g '  7 | func main() {'")
+	clipSymbolCmd.Flags().Bool("dry-run", false, "Lists the snippets that would be clipped, with their size, lines and tokens, without clipping them")
+}
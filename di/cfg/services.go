@@ -6,7 +6,9 @@ import (
 	"github.com/dembygenesis/local.tools/internal/config"
 	"github.com/dembygenesis/local.tools/internal/services/file_utils"
 	"github.com/dembygenesis/local.tools/internal/services/gpt_utils"
+	"github.com/dembygenesis/local.tools/internal/services/history_utils"
 	"github.com/dembygenesis/local.tools/internal/services/string_utils"
+	"github.com/dembygenesis/local.tools/internal/utils_common"
 	"github.com/sarulabs/dingo/v4"
 )
 
@@ -26,15 +28,27 @@ func getServicesLayer() []dingo.Def {
 					return nil, err
 				}
 
-				stringUtils, err := string_utils.New(cfg, wrappers.NewStringUtilsWrapper())
+				history := utils_common.NewHistory(
+					cfg.ClipHistory.Dir,
+					cfg.ClipHistory.MaxEntries,
+					cfg.ClipHistory.MaxAge,
+				)
+
+				stringUtils, err := string_utils.New(cfg, wrappers.NewStringUtilsWrapper(history))
+				if err != nil {
+					return nil, err
+				}
+
+				historyUtils, err := history_utils.New(cfg, wrappers.NewHistoryWrapper(history))
 				if err != nil {
 					return nil, err
 				}
 
 				return cli.NewService(
 					stringUtils,
-					gpt_utils.New(),
+					gpt_utils.New(history),
 					fileUtils,
+					historyUtils,
 				), nil
 			},
 		},
@@ -14,3 +14,27 @@ type FileWrapper struct {
 func (f *FileWrapper) CopyDirToAnother(opts *utils_common.CopyOptions) error {
 	return utils_common.CopyDirToAnother(opts)
 }
+
+func (f *FileWrapper) ReadClipboard() (string, error) {
+	return utils_common.ReadClipboard()
+}
+
+func (f *FileWrapper) ParseBundle(text string) ([]utils_common.BundleFile, error) {
+	return utils_common.ParseBundle(text)
+}
+
+func (f *FileWrapper) PlanBundle(root string, files []utils_common.BundleFile) (*utils_common.ApplyPlan, error) {
+	return utils_common.PlanBundle(root, files)
+}
+
+func (f *FileWrapper) ApplyBundle(plan *utils_common.ApplyPlan) error {
+	return utils_common.ApplyBundle(plan)
+}
+
+func (f *FileWrapper) ParsePatch(text string) ([]utils_common.FilePatch, error) {
+	return utils_common.ParsePatch(text)
+}
+
+func (f *FileWrapper) ApplyPatch(root string, patches []utils_common.FilePatch, check bool) (*utils_common.PatchResult, error) {
+	return utils_common.ApplyPatch(root, patches, check)
+}
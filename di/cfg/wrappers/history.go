@@ -0,0 +1,25 @@
+package wrappers
+
+import (
+	"github.com/dembygenesis/local.tools/internal/utils_common"
+)
+
+func NewHistoryWrapper(history *utils_common.History) *HistoryWrapper {
+	return &HistoryWrapper{history}
+}
+
+type HistoryWrapper struct {
+	history *utils_common.History
+}
+
+func (h *HistoryWrapper) List() ([]utils_common.HistoryEntry, error) {
+	return h.history.List()
+}
+
+func (h *HistoryWrapper) Get(id int) (*utils_common.HistoryEntry, string, error) {
+	return h.history.Get(id)
+}
+
+func (h *HistoryWrapper) Restore(id int) (*utils_common.HistoryEntry, error) {
+	return h.history.Restore(id)
+}
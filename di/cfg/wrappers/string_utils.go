@@ -1,16 +1,39 @@
 package wrappers
 
 import (
+	"context"
 	"github.com/dembygenesis/local.tools/internal/utils_common"
+	"io"
 )
 
-func NewStringUtilsWrapper() *StringWrapper {
-	return &StringWrapper{}
+func NewStringUtilsWrapper(history *utils_common.History) *StringWrapper {
+	return &StringWrapper{history}
 }
 
 type StringWrapper struct {
+	history *utils_common.History
 }
 
-func (f *StringWrapper) CopyRootPathToClipboard(root string, exclude []string) ([]string, error) {
-	return utils_common.CopyRootPathToClipboard(root, exclude)
+func (f *StringWrapper) BuildBundle(ctx context.Context, opts *utils_common.ClipOptions) (*utils_common.Bundle, error) {
+	return utils_common.BuildBundle(ctx, opts)
+}
+
+func (f *StringWrapper) ClipBundle(bundle *utils_common.Bundle) error {
+	return utils_common.ClipBundle(bundle, f.history)
+}
+
+func (f *StringWrapper) WriteBundle(bundle *utils_common.Bundle, w io.Writer) error {
+	return utils_common.WriteBundle(bundle, w)
+}
+
+func (f *StringWrapper) WriteBundleToFile(bundle *utils_common.Bundle, path string) error {
+	return utils_common.WriteBundleToFile(bundle, path)
+}
+
+func (f *StringWrapper) WriteBundleToDir(bundle *utils_common.Bundle, dir string) error {
+	return utils_common.WriteBundleToDir(bundle, dir)
+}
+
+func (f *StringWrapper) WriteArchive(ctx context.Context, opts *utils_common.ClipOptions) (*utils_common.ArchiveResult, error) {
+	return utils_common.WriteArchive(ctx, opts)
 }
@@ -1,14 +1,21 @@
 package cli
 
 import (
+	"context"
 	"github.com/dembygenesis/local.tools/internal/utils_common"
+	"io"
 )
 
 //go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 -generate
 
 //counterfeiter:generate . stringUtils
 type stringUtils interface {
-	CopyRootPathToClipboard(root string, exclude []string) ([]string, error)
+	BuildBundle(ctx context.Context, opts *utils_common.ClipOptions) (*utils_common.Bundle, error)
+	ClipBundle(bundle *utils_common.Bundle) error
+	WriteBundle(bundle *utils_common.Bundle, w io.Writer) error
+	WriteBundleToFile(bundle *utils_common.Bundle, path string) error
+	WriteBundleToDir(bundle *utils_common.Bundle, dir string) error
+	WriteArchive(ctx context.Context, opts *utils_common.ClipOptions) (*utils_common.ArchiveResult, error)
 }
 
 //counterfeiter:generate . gptUtils
@@ -19,4 +26,14 @@ type gptUtils interface {
 //counterfeiter:generate . fileUtils
 type fileUtils interface {
 	CopyDirToAnother(opts *utils_common.CopyOptions) error
+	PlanApply(opts *utils_common.ApplyOptions, stdin io.Reader) (*utils_common.ApplyPlan, error)
+	Apply(plan *utils_common.ApplyPlan) error
+	ApplyPatch(opts *utils_common.PatchOptions, stdin io.Reader) (*utils_common.PatchResult, error)
+}
+
+//counterfeiter:generate . historyUtils
+type historyUtils interface {
+	List() ([]utils_common.HistoryEntry, error)
+	Show(id int) (*utils_common.HistoryEntry, string, error)
+	Restore(id int) (*utils_common.HistoryEntry, error)
 }
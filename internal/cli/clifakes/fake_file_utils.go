@@ -2,12 +2,38 @@
 package clifakes
 
 import (
+	"io"
 	"sync"
 
 	"github.com/dembygenesis/local.tools/internal/utils_common"
 )
 
 type FakeFileUtils struct {
+	ApplyStub        func(*utils_common.ApplyPlan) error
+	applyMutex       sync.RWMutex
+	applyArgsForCall []struct {
+		arg1 *utils_common.ApplyPlan
+	}
+	applyReturns struct {
+		result1 error
+	}
+	applyReturnsOnCall map[int]struct {
+		result1 error
+	}
+	ApplyPatchStub        func(*utils_common.PatchOptions, io.Reader) (*utils_common.PatchResult, error)
+	applyPatchMutex       sync.RWMutex
+	applyPatchArgsForCall []struct {
+		arg1 *utils_common.PatchOptions
+		arg2 io.Reader
+	}
+	applyPatchReturns struct {
+		result1 *utils_common.PatchResult
+		result2 error
+	}
+	applyPatchReturnsOnCall map[int]struct {
+		result1 *utils_common.PatchResult
+		result2 error
+	}
 	CopyDirToAnotherStub        func(*utils_common.CopyOptions) error
 	copyDirToAnotherMutex       sync.RWMutex
 	copyDirToAnotherArgsForCall []struct {
@@ -19,10 +45,150 @@ type FakeFileUtils struct {
 	copyDirToAnotherReturnsOnCall map[int]struct {
 		result1 error
 	}
+	PlanApplyStub        func(*utils_common.ApplyOptions, io.Reader) (*utils_common.ApplyPlan, error)
+	planApplyMutex       sync.RWMutex
+	planApplyArgsForCall []struct {
+		arg1 *utils_common.ApplyOptions
+		arg2 io.Reader
+	}
+	planApplyReturns struct {
+		result1 *utils_common.ApplyPlan
+		result2 error
+	}
+	planApplyReturnsOnCall map[int]struct {
+		result1 *utils_common.ApplyPlan
+		result2 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
 
+func (fake *FakeFileUtils) Apply(arg1 *utils_common.ApplyPlan) error {
+	fake.applyMutex.Lock()
+	ret, specificReturn := fake.applyReturnsOnCall[len(fake.applyArgsForCall)]
+	fake.applyArgsForCall = append(fake.applyArgsForCall, struct {
+		arg1 *utils_common.ApplyPlan
+	}{arg1})
+	stub := fake.ApplyStub
+	fakeReturns := fake.applyReturns
+	fake.recordInvocation("Apply", []interface{}{arg1})
+	fake.applyMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeFileUtils) ApplyCallCount() int {
+	fake.applyMutex.RLock()
+	defer fake.applyMutex.RUnlock()
+	return len(fake.applyArgsForCall)
+}
+
+func (fake *FakeFileUtils) ApplyCalls(stub func(*utils_common.ApplyPlan) error) {
+	fake.applyMutex.Lock()
+	defer fake.applyMutex.Unlock()
+	fake.ApplyStub = stub
+}
+
+func (fake *FakeFileUtils) ApplyArgsForCall(i int) *utils_common.ApplyPlan {
+	fake.applyMutex.RLock()
+	defer fake.applyMutex.RUnlock()
+	argsForCall := fake.applyArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeFileUtils) ApplyReturns(result1 error) {
+	fake.applyMutex.Lock()
+	defer fake.applyMutex.Unlock()
+	fake.ApplyStub = nil
+	fake.applyReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeFileUtils) ApplyReturnsOnCall(i int, result1 error) {
+	fake.applyMutex.Lock()
+	defer fake.applyMutex.Unlock()
+	fake.ApplyStub = nil
+	if fake.applyReturnsOnCall == nil {
+		fake.applyReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.applyReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeFileUtils) ApplyPatch(arg1 *utils_common.PatchOptions, arg2 io.Reader) (*utils_common.PatchResult, error) {
+	fake.applyPatchMutex.Lock()
+	ret, specificReturn := fake.applyPatchReturnsOnCall[len(fake.applyPatchArgsForCall)]
+	fake.applyPatchArgsForCall = append(fake.applyPatchArgsForCall, struct {
+		arg1 *utils_common.PatchOptions
+		arg2 io.Reader
+	}{arg1, arg2})
+	stub := fake.ApplyPatchStub
+	fakeReturns := fake.applyPatchReturns
+	fake.recordInvocation("ApplyPatch", []interface{}{arg1, arg2})
+	fake.applyPatchMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeFileUtils) ApplyPatchCallCount() int {
+	fake.applyPatchMutex.RLock()
+	defer fake.applyPatchMutex.RUnlock()
+	return len(fake.applyPatchArgsForCall)
+}
+
+func (fake *FakeFileUtils) ApplyPatchCalls(stub func(*utils_common.PatchOptions, io.Reader) (*utils_common.PatchResult, error)) {
+	fake.applyPatchMutex.Lock()
+	defer fake.applyPatchMutex.Unlock()
+	fake.ApplyPatchStub = stub
+}
+
+func (fake *FakeFileUtils) ApplyPatchArgsForCall(i int) (*utils_common.PatchOptions, io.Reader) {
+	fake.applyPatchMutex.RLock()
+	defer fake.applyPatchMutex.RUnlock()
+	argsForCall := fake.applyPatchArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeFileUtils) ApplyPatchReturns(result1 *utils_common.PatchResult, result2 error) {
+	fake.applyPatchMutex.Lock()
+	defer fake.applyPatchMutex.Unlock()
+	fake.ApplyPatchStub = nil
+	fake.applyPatchReturns = struct {
+		result1 *utils_common.PatchResult
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeFileUtils) ApplyPatchReturnsOnCall(i int, result1 *utils_common.PatchResult, result2 error) {
+	fake.applyPatchMutex.Lock()
+	defer fake.applyPatchMutex.Unlock()
+	fake.ApplyPatchStub = nil
+	if fake.applyPatchReturnsOnCall == nil {
+		fake.applyPatchReturnsOnCall = make(map[int]struct {
+			result1 *utils_common.PatchResult
+			result2 error
+		})
+	}
+	fake.applyPatchReturnsOnCall[i] = struct {
+		result1 *utils_common.PatchResult
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeFileUtils) CopyDirToAnother(arg1 *utils_common.CopyOptions) error {
 	fake.copyDirToAnotherMutex.Lock()
 	ret, specificReturn := fake.copyDirToAnotherReturnsOnCall[len(fake.copyDirToAnotherArgsForCall)]
@@ -84,11 +250,82 @@ func (fake *FakeFileUtils) CopyDirToAnotherReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *FakeFileUtils) PlanApply(arg1 *utils_common.ApplyOptions, arg2 io.Reader) (*utils_common.ApplyPlan, error) {
+	fake.planApplyMutex.Lock()
+	ret, specificReturn := fake.planApplyReturnsOnCall[len(fake.planApplyArgsForCall)]
+	fake.planApplyArgsForCall = append(fake.planApplyArgsForCall, struct {
+		arg1 *utils_common.ApplyOptions
+		arg2 io.Reader
+	}{arg1, arg2})
+	stub := fake.PlanApplyStub
+	fakeReturns := fake.planApplyReturns
+	fake.recordInvocation("PlanApply", []interface{}{arg1, arg2})
+	fake.planApplyMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeFileUtils) PlanApplyCallCount() int {
+	fake.planApplyMutex.RLock()
+	defer fake.planApplyMutex.RUnlock()
+	return len(fake.planApplyArgsForCall)
+}
+
+func (fake *FakeFileUtils) PlanApplyCalls(stub func(*utils_common.ApplyOptions, io.Reader) (*utils_common.ApplyPlan, error)) {
+	fake.planApplyMutex.Lock()
+	defer fake.planApplyMutex.Unlock()
+	fake.PlanApplyStub = stub
+}
+
+func (fake *FakeFileUtils) PlanApplyArgsForCall(i int) (*utils_common.ApplyOptions, io.Reader) {
+	fake.planApplyMutex.RLock()
+	defer fake.planApplyMutex.RUnlock()
+	argsForCall := fake.planApplyArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeFileUtils) PlanApplyReturns(result1 *utils_common.ApplyPlan, result2 error) {
+	fake.planApplyMutex.Lock()
+	defer fake.planApplyMutex.Unlock()
+	fake.PlanApplyStub = nil
+	fake.planApplyReturns = struct {
+		result1 *utils_common.ApplyPlan
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeFileUtils) PlanApplyReturnsOnCall(i int, result1 *utils_common.ApplyPlan, result2 error) {
+	fake.planApplyMutex.Lock()
+	defer fake.planApplyMutex.Unlock()
+	fake.PlanApplyStub = nil
+	if fake.planApplyReturnsOnCall == nil {
+		fake.planApplyReturnsOnCall = make(map[int]struct {
+			result1 *utils_common.ApplyPlan
+			result2 error
+		})
+	}
+	fake.planApplyReturnsOnCall[i] = struct {
+		result1 *utils_common.ApplyPlan
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeFileUtils) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
+	fake.applyMutex.RLock()
+	defer fake.applyMutex.RUnlock()
+	fake.applyPatchMutex.RLock()
+	defer fake.applyPatchMutex.RUnlock()
 	fake.copyDirToAnotherMutex.RLock()
 	defer fake.copyDirToAnotherMutex.RUnlock()
+	fake.planApplyMutex.RLock()
+	defer fake.planApplyMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
@@ -0,0 +1,268 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package clifakes
+
+import (
+	"sync"
+
+	"github.com/dembygenesis/local.tools/internal/utils_common"
+)
+
+type FakeHistoryUtils struct {
+	ListStub        func() ([]utils_common.HistoryEntry, error)
+	listMutex       sync.RWMutex
+	listArgsForCall []struct {
+	}
+	listReturns struct {
+		result1 []utils_common.HistoryEntry
+		result2 error
+	}
+	listReturnsOnCall map[int]struct {
+		result1 []utils_common.HistoryEntry
+		result2 error
+	}
+	RestoreStub        func(int) (*utils_common.HistoryEntry, error)
+	restoreMutex       sync.RWMutex
+	restoreArgsForCall []struct {
+		arg1 int
+	}
+	restoreReturns struct {
+		result1 *utils_common.HistoryEntry
+		result2 error
+	}
+	restoreReturnsOnCall map[int]struct {
+		result1 *utils_common.HistoryEntry
+		result2 error
+	}
+	ShowStub        func(int) (*utils_common.HistoryEntry, string, error)
+	showMutex       sync.RWMutex
+	showArgsForCall []struct {
+		arg1 int
+	}
+	showReturns struct {
+		result1 *utils_common.HistoryEntry
+		result2 string
+		result3 error
+	}
+	showReturnsOnCall map[int]struct {
+		result1 *utils_common.HistoryEntry
+		result2 string
+		result3 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeHistoryUtils) List() ([]utils_common.HistoryEntry, error) {
+	fake.listMutex.Lock()
+	ret, specificReturn := fake.listReturnsOnCall[len(fake.listArgsForCall)]
+	fake.listArgsForCall = append(fake.listArgsForCall, struct {
+	}{})
+	stub := fake.ListStub
+	fakeReturns := fake.listReturns
+	fake.recordInvocation("List", []interface{}{})
+	fake.listMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeHistoryUtils) ListCallCount() int {
+	fake.listMutex.RLock()
+	defer fake.listMutex.RUnlock()
+	return len(fake.listArgsForCall)
+}
+
+func (fake *FakeHistoryUtils) ListCalls(stub func() ([]utils_common.HistoryEntry, error)) {
+	fake.listMutex.Lock()
+	defer fake.listMutex.Unlock()
+	fake.ListStub = stub
+}
+
+func (fake *FakeHistoryUtils) ListReturns(result1 []utils_common.HistoryEntry, result2 error) {
+	fake.listMutex.Lock()
+	defer fake.listMutex.Unlock()
+	fake.ListStub = nil
+	fake.listReturns = struct {
+		result1 []utils_common.HistoryEntry
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeHistoryUtils) ListReturnsOnCall(i int, result1 []utils_common.HistoryEntry, result2 error) {
+	fake.listMutex.Lock()
+	defer fake.listMutex.Unlock()
+	fake.ListStub = nil
+	if fake.listReturnsOnCall == nil {
+		fake.listReturnsOnCall = make(map[int]struct {
+			result1 []utils_common.HistoryEntry
+			result2 error
+		})
+	}
+	fake.listReturnsOnCall[i] = struct {
+		result1 []utils_common.HistoryEntry
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeHistoryUtils) Restore(arg1 int) (*utils_common.HistoryEntry, error) {
+	fake.restoreMutex.Lock()
+	ret, specificReturn := fake.restoreReturnsOnCall[len(fake.restoreArgsForCall)]
+	fake.restoreArgsForCall = append(fake.restoreArgsForCall, struct {
+		arg1 int
+	}{arg1})
+	stub := fake.RestoreStub
+	fakeReturns := fake.restoreReturns
+	fake.recordInvocation("Restore", []interface{}{arg1})
+	fake.restoreMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeHistoryUtils) RestoreCallCount() int {
+	fake.restoreMutex.RLock()
+	defer fake.restoreMutex.RUnlock()
+	return len(fake.restoreArgsForCall)
+}
+
+func (fake *FakeHistoryUtils) RestoreCalls(stub func(int) (*utils_common.HistoryEntry, error)) {
+	fake.restoreMutex.Lock()
+	defer fake.restoreMutex.Unlock()
+	fake.RestoreStub = stub
+}
+
+func (fake *FakeHistoryUtils) RestoreArgsForCall(i int) int {
+	fake.restoreMutex.RLock()
+	defer fake.restoreMutex.RUnlock()
+	argsForCall := fake.restoreArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeHistoryUtils) RestoreReturns(result1 *utils_common.HistoryEntry, result2 error) {
+	fake.restoreMutex.Lock()
+	defer fake.restoreMutex.Unlock()
+	fake.RestoreStub = nil
+	fake.restoreReturns = struct {
+		result1 *utils_common.HistoryEntry
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeHistoryUtils) RestoreReturnsOnCall(i int, result1 *utils_common.HistoryEntry, result2 error) {
+	fake.restoreMutex.Lock()
+	defer fake.restoreMutex.Unlock()
+	fake.RestoreStub = nil
+	if fake.restoreReturnsOnCall == nil {
+		fake.restoreReturnsOnCall = make(map[int]struct {
+			result1 *utils_common.HistoryEntry
+			result2 error
+		})
+	}
+	fake.restoreReturnsOnCall[i] = struct {
+		result1 *utils_common.HistoryEntry
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeHistoryUtils) Show(arg1 int) (*utils_common.HistoryEntry, string, error) {
+	fake.showMutex.Lock()
+	ret, specificReturn := fake.showReturnsOnCall[len(fake.showArgsForCall)]
+	fake.showArgsForCall = append(fake.showArgsForCall, struct {
+		arg1 int
+	}{arg1})
+	stub := fake.ShowStub
+	fakeReturns := fake.showReturns
+	fake.recordInvocation("Show", []interface{}{arg1})
+	fake.showMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2, ret.result3
+	}
+	return fakeReturns.result1, fakeReturns.result2, fakeReturns.result3
+}
+
+func (fake *FakeHistoryUtils) ShowCallCount() int {
+	fake.showMutex.RLock()
+	defer fake.showMutex.RUnlock()
+	return len(fake.showArgsForCall)
+}
+
+func (fake *FakeHistoryUtils) ShowCalls(stub func(int) (*utils_common.HistoryEntry, string, error)) {
+	fake.showMutex.Lock()
+	defer fake.showMutex.Unlock()
+	fake.ShowStub = stub
+}
+
+func (fake *FakeHistoryUtils) ShowArgsForCall(i int) int {
+	fake.showMutex.RLock()
+	defer fake.showMutex.RUnlock()
+	argsForCall := fake.showArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeHistoryUtils) ShowReturns(result1 *utils_common.HistoryEntry, result2 string, result3 error) {
+	fake.showMutex.Lock()
+	defer fake.showMutex.Unlock()
+	fake.ShowStub = nil
+	fake.showReturns = struct {
+		result1 *utils_common.HistoryEntry
+		result2 string
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeHistoryUtils) ShowReturnsOnCall(i int, result1 *utils_common.HistoryEntry, result2 string, result3 error) {
+	fake.showMutex.Lock()
+	defer fake.showMutex.Unlock()
+	fake.ShowStub = nil
+	if fake.showReturnsOnCall == nil {
+		fake.showReturnsOnCall = make(map[int]struct {
+			result1 *utils_common.HistoryEntry
+			result2 string
+			result3 error
+		})
+	}
+	fake.showReturnsOnCall[i] = struct {
+		result1 *utils_common.HistoryEntry
+		result2 string
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeHistoryUtils) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.listMutex.RLock()
+	defer fake.listMutex.RUnlock()
+	fake.restoreMutex.RLock()
+	defer fake.restoreMutex.RUnlock()
+	fake.showMutex.RLock()
+	defer fake.showMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeHistoryUtils) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
@@ -2,44 +2,230 @@
 package clifakes
 
 import (
+	"context"
+	"io"
 	"sync"
+
+	"github.com/dembygenesis/local.tools/internal/utils_common"
 )
 
 type FakeStringUtils struct {
-	CopyRootPathToClipboardStub        func(string, []string) ([]string, error)
-	copyRootPathToClipboardMutex       sync.RWMutex
-	copyRootPathToClipboardArgsForCall []struct {
-		arg1 string
-		arg2 []string
-	}
-	copyRootPathToClipboardReturns struct {
-		result1 []string
+	BuildBundleStub        func(context.Context, *utils_common.ClipOptions) (*utils_common.Bundle, error)
+	buildBundleMutex       sync.RWMutex
+	buildBundleArgsForCall []struct {
+		arg1 context.Context
+		arg2 *utils_common.ClipOptions
+	}
+	buildBundleReturns struct {
+		result1 *utils_common.Bundle
+		result2 error
+	}
+	buildBundleReturnsOnCall map[int]struct {
+		result1 *utils_common.Bundle
+		result2 error
+	}
+	ClipBundleStub        func(*utils_common.Bundle) error
+	clipBundleMutex       sync.RWMutex
+	clipBundleArgsForCall []struct {
+		arg1 *utils_common.Bundle
+	}
+	clipBundleReturns struct {
+		result1 error
+	}
+	clipBundleReturnsOnCall map[int]struct {
+		result1 error
+	}
+	WriteArchiveStub        func(context.Context, *utils_common.ClipOptions) (*utils_common.ArchiveResult, error)
+	writeArchiveMutex       sync.RWMutex
+	writeArchiveArgsForCall []struct {
+		arg1 context.Context
+		arg2 *utils_common.ClipOptions
+	}
+	writeArchiveReturns struct {
+		result1 *utils_common.ArchiveResult
 		result2 error
 	}
-	copyRootPathToClipboardReturnsOnCall map[int]struct {
-		result1 []string
+	writeArchiveReturnsOnCall map[int]struct {
+		result1 *utils_common.ArchiveResult
 		result2 error
 	}
+	WriteBundleStub        func(*utils_common.Bundle, io.Writer) error
+	writeBundleMutex       sync.RWMutex
+	writeBundleArgsForCall []struct {
+		arg1 *utils_common.Bundle
+		arg2 io.Writer
+	}
+	writeBundleReturns struct {
+		result1 error
+	}
+	writeBundleReturnsOnCall map[int]struct {
+		result1 error
+	}
+	WriteBundleToDirStub        func(*utils_common.Bundle, string) error
+	writeBundleToDirMutex       sync.RWMutex
+	writeBundleToDirArgsForCall []struct {
+		arg1 *utils_common.Bundle
+		arg2 string
+	}
+	writeBundleToDirReturns struct {
+		result1 error
+	}
+	writeBundleToDirReturnsOnCall map[int]struct {
+		result1 error
+	}
+	WriteBundleToFileStub        func(*utils_common.Bundle, string) error
+	writeBundleToFileMutex       sync.RWMutex
+	writeBundleToFileArgsForCall []struct {
+		arg1 *utils_common.Bundle
+		arg2 string
+	}
+	writeBundleToFileReturns struct {
+		result1 error
+	}
+	writeBundleToFileReturnsOnCall map[int]struct {
+		result1 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *FakeStringUtils) CopyRootPathToClipboard(arg1 string, arg2 []string) ([]string, error) {
-	var arg2Copy []string
-	if arg2 != nil {
-		arg2Copy = make([]string, len(arg2))
-		copy(arg2Copy, arg2)
-	}
-	fake.copyRootPathToClipboardMutex.Lock()
-	ret, specificReturn := fake.copyRootPathToClipboardReturnsOnCall[len(fake.copyRootPathToClipboardArgsForCall)]
-	fake.copyRootPathToClipboardArgsForCall = append(fake.copyRootPathToClipboardArgsForCall, struct {
-		arg1 string
-		arg2 []string
-	}{arg1, arg2Copy})
-	stub := fake.CopyRootPathToClipboardStub
-	fakeReturns := fake.copyRootPathToClipboardReturns
-	fake.recordInvocation("CopyRootPathToClipboard", []interface{}{arg1, arg2Copy})
-	fake.copyRootPathToClipboardMutex.Unlock()
+func (fake *FakeStringUtils) BuildBundle(arg1 context.Context, arg2 *utils_common.ClipOptions) (*utils_common.Bundle, error) {
+	fake.buildBundleMutex.Lock()
+	ret, specificReturn := fake.buildBundleReturnsOnCall[len(fake.buildBundleArgsForCall)]
+	fake.buildBundleArgsForCall = append(fake.buildBundleArgsForCall, struct {
+		arg1 context.Context
+		arg2 *utils_common.ClipOptions
+	}{arg1, arg2})
+	stub := fake.BuildBundleStub
+	fakeReturns := fake.buildBundleReturns
+	fake.recordInvocation("BuildBundle", []interface{}{arg1, arg2})
+	fake.buildBundleMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeStringUtils) BuildBundleCallCount() int {
+	fake.buildBundleMutex.RLock()
+	defer fake.buildBundleMutex.RUnlock()
+	return len(fake.buildBundleArgsForCall)
+}
+
+func (fake *FakeStringUtils) BuildBundleCalls(stub func(context.Context, *utils_common.ClipOptions) (*utils_common.Bundle, error)) {
+	fake.buildBundleMutex.Lock()
+	defer fake.buildBundleMutex.Unlock()
+	fake.BuildBundleStub = stub
+}
+
+func (fake *FakeStringUtils) BuildBundleArgsForCall(i int) (context.Context, *utils_common.ClipOptions) {
+	fake.buildBundleMutex.RLock()
+	defer fake.buildBundleMutex.RUnlock()
+	argsForCall := fake.buildBundleArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeStringUtils) BuildBundleReturns(result1 *utils_common.Bundle, result2 error) {
+	fake.buildBundleMutex.Lock()
+	defer fake.buildBundleMutex.Unlock()
+	fake.BuildBundleStub = nil
+	fake.buildBundleReturns = struct {
+		result1 *utils_common.Bundle
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeStringUtils) BuildBundleReturnsOnCall(i int, result1 *utils_common.Bundle, result2 error) {
+	fake.buildBundleMutex.Lock()
+	defer fake.buildBundleMutex.Unlock()
+	fake.BuildBundleStub = nil
+	if fake.buildBundleReturnsOnCall == nil {
+		fake.buildBundleReturnsOnCall = make(map[int]struct {
+			result1 *utils_common.Bundle
+			result2 error
+		})
+	}
+	fake.buildBundleReturnsOnCall[i] = struct {
+		result1 *utils_common.Bundle
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeStringUtils) ClipBundle(arg1 *utils_common.Bundle) error {
+	fake.clipBundleMutex.Lock()
+	ret, specificReturn := fake.clipBundleReturnsOnCall[len(fake.clipBundleArgsForCall)]
+	fake.clipBundleArgsForCall = append(fake.clipBundleArgsForCall, struct {
+		arg1 *utils_common.Bundle
+	}{arg1})
+	stub := fake.ClipBundleStub
+	fakeReturns := fake.clipBundleReturns
+	fake.recordInvocation("ClipBundle", []interface{}{arg1})
+	fake.clipBundleMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeStringUtils) ClipBundleCallCount() int {
+	fake.clipBundleMutex.RLock()
+	defer fake.clipBundleMutex.RUnlock()
+	return len(fake.clipBundleArgsForCall)
+}
+
+func (fake *FakeStringUtils) ClipBundleCalls(stub func(*utils_common.Bundle) error) {
+	fake.clipBundleMutex.Lock()
+	defer fake.clipBundleMutex.Unlock()
+	fake.ClipBundleStub = stub
+}
+
+func (fake *FakeStringUtils) ClipBundleArgsForCall(i int) *utils_common.Bundle {
+	fake.clipBundleMutex.RLock()
+	defer fake.clipBundleMutex.RUnlock()
+	argsForCall := fake.clipBundleArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeStringUtils) ClipBundleReturns(result1 error) {
+	fake.clipBundleMutex.Lock()
+	defer fake.clipBundleMutex.Unlock()
+	fake.ClipBundleStub = nil
+	fake.clipBundleReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeStringUtils) ClipBundleReturnsOnCall(i int, result1 error) {
+	fake.clipBundleMutex.Lock()
+	defer fake.clipBundleMutex.Unlock()
+	fake.ClipBundleStub = nil
+	if fake.clipBundleReturnsOnCall == nil {
+		fake.clipBundleReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.clipBundleReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeStringUtils) WriteArchive(arg1 context.Context, arg2 *utils_common.ClipOptions) (*utils_common.ArchiveResult, error) {
+	fake.writeArchiveMutex.Lock()
+	ret, specificReturn := fake.writeArchiveReturnsOnCall[len(fake.writeArchiveArgsForCall)]
+	fake.writeArchiveArgsForCall = append(fake.writeArchiveArgsForCall, struct {
+		arg1 context.Context
+		arg2 *utils_common.ClipOptions
+	}{arg1, arg2})
+	stub := fake.WriteArchiveStub
+	fakeReturns := fake.writeArchiveReturns
+	fake.recordInvocation("WriteArchive", []interface{}{arg1, arg2})
+	fake.writeArchiveMutex.Unlock()
 	if stub != nil {
 		return stub(arg1, arg2)
 	}
@@ -49,56 +235,252 @@ func (fake *FakeStringUtils) CopyRootPathToClipboard(arg1 string, arg2 []string)
 	return fakeReturns.result1, fakeReturns.result2
 }
 
-func (fake *FakeStringUtils) CopyRootPathToClipboardCallCount() int {
-	fake.copyRootPathToClipboardMutex.RLock()
-	defer fake.copyRootPathToClipboardMutex.RUnlock()
-	return len(fake.copyRootPathToClipboardArgsForCall)
+func (fake *FakeStringUtils) WriteArchiveCallCount() int {
+	fake.writeArchiveMutex.RLock()
+	defer fake.writeArchiveMutex.RUnlock()
+	return len(fake.writeArchiveArgsForCall)
 }
 
-func (fake *FakeStringUtils) CopyRootPathToClipboardCalls(stub func(string, []string) ([]string, error)) {
-	fake.copyRootPathToClipboardMutex.Lock()
-	defer fake.copyRootPathToClipboardMutex.Unlock()
-	fake.CopyRootPathToClipboardStub = stub
+func (fake *FakeStringUtils) WriteArchiveCalls(stub func(context.Context, *utils_common.ClipOptions) (*utils_common.ArchiveResult, error)) {
+	fake.writeArchiveMutex.Lock()
+	defer fake.writeArchiveMutex.Unlock()
+	fake.WriteArchiveStub = stub
 }
 
-func (fake *FakeStringUtils) CopyRootPathToClipboardArgsForCall(i int) (string, []string) {
-	fake.copyRootPathToClipboardMutex.RLock()
-	defer fake.copyRootPathToClipboardMutex.RUnlock()
-	argsForCall := fake.copyRootPathToClipboardArgsForCall[i]
+func (fake *FakeStringUtils) WriteArchiveArgsForCall(i int) (context.Context, *utils_common.ClipOptions) {
+	fake.writeArchiveMutex.RLock()
+	defer fake.writeArchiveMutex.RUnlock()
+	argsForCall := fake.writeArchiveArgsForCall[i]
 	return argsForCall.arg1, argsForCall.arg2
 }
 
-func (fake *FakeStringUtils) CopyRootPathToClipboardReturns(result1 []string, result2 error) {
-	fake.copyRootPathToClipboardMutex.Lock()
-	defer fake.copyRootPathToClipboardMutex.Unlock()
-	fake.CopyRootPathToClipboardStub = nil
-	fake.copyRootPathToClipboardReturns = struct {
-		result1 []string
+func (fake *FakeStringUtils) WriteArchiveReturns(result1 *utils_common.ArchiveResult, result2 error) {
+	fake.writeArchiveMutex.Lock()
+	defer fake.writeArchiveMutex.Unlock()
+	fake.WriteArchiveStub = nil
+	fake.writeArchiveReturns = struct {
+		result1 *utils_common.ArchiveResult
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *FakeStringUtils) CopyRootPathToClipboardReturnsOnCall(i int, result1 []string, result2 error) {
-	fake.copyRootPathToClipboardMutex.Lock()
-	defer fake.copyRootPathToClipboardMutex.Unlock()
-	fake.CopyRootPathToClipboardStub = nil
-	if fake.copyRootPathToClipboardReturnsOnCall == nil {
-		fake.copyRootPathToClipboardReturnsOnCall = make(map[int]struct {
-			result1 []string
+func (fake *FakeStringUtils) WriteArchiveReturnsOnCall(i int, result1 *utils_common.ArchiveResult, result2 error) {
+	fake.writeArchiveMutex.Lock()
+	defer fake.writeArchiveMutex.Unlock()
+	fake.WriteArchiveStub = nil
+	if fake.writeArchiveReturnsOnCall == nil {
+		fake.writeArchiveReturnsOnCall = make(map[int]struct {
+			result1 *utils_common.ArchiveResult
 			result2 error
 		})
 	}
-	fake.copyRootPathToClipboardReturnsOnCall[i] = struct {
-		result1 []string
+	fake.writeArchiveReturnsOnCall[i] = struct {
+		result1 *utils_common.ArchiveResult
 		result2 error
 	}{result1, result2}
 }
 
+func (fake *FakeStringUtils) WriteBundle(arg1 *utils_common.Bundle, arg2 io.Writer) error {
+	fake.writeBundleMutex.Lock()
+	ret, specificReturn := fake.writeBundleReturnsOnCall[len(fake.writeBundleArgsForCall)]
+	fake.writeBundleArgsForCall = append(fake.writeBundleArgsForCall, struct {
+		arg1 *utils_common.Bundle
+		arg2 io.Writer
+	}{arg1, arg2})
+	stub := fake.WriteBundleStub
+	fakeReturns := fake.writeBundleReturns
+	fake.recordInvocation("WriteBundle", []interface{}{arg1, arg2})
+	fake.writeBundleMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeStringUtils) WriteBundleCallCount() int {
+	fake.writeBundleMutex.RLock()
+	defer fake.writeBundleMutex.RUnlock()
+	return len(fake.writeBundleArgsForCall)
+}
+
+func (fake *FakeStringUtils) WriteBundleCalls(stub func(*utils_common.Bundle, io.Writer) error) {
+	fake.writeBundleMutex.Lock()
+	defer fake.writeBundleMutex.Unlock()
+	fake.WriteBundleStub = stub
+}
+
+func (fake *FakeStringUtils) WriteBundleArgsForCall(i int) (*utils_common.Bundle, io.Writer) {
+	fake.writeBundleMutex.RLock()
+	defer fake.writeBundleMutex.RUnlock()
+	argsForCall := fake.writeBundleArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeStringUtils) WriteBundleReturns(result1 error) {
+	fake.writeBundleMutex.Lock()
+	defer fake.writeBundleMutex.Unlock()
+	fake.WriteBundleStub = nil
+	fake.writeBundleReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeStringUtils) WriteBundleReturnsOnCall(i int, result1 error) {
+	fake.writeBundleMutex.Lock()
+	defer fake.writeBundleMutex.Unlock()
+	fake.WriteBundleStub = nil
+	if fake.writeBundleReturnsOnCall == nil {
+		fake.writeBundleReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.writeBundleReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeStringUtils) WriteBundleToDir(arg1 *utils_common.Bundle, arg2 string) error {
+	fake.writeBundleToDirMutex.Lock()
+	ret, specificReturn := fake.writeBundleToDirReturnsOnCall[len(fake.writeBundleToDirArgsForCall)]
+	fake.writeBundleToDirArgsForCall = append(fake.writeBundleToDirArgsForCall, struct {
+		arg1 *utils_common.Bundle
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.WriteBundleToDirStub
+	fakeReturns := fake.writeBundleToDirReturns
+	fake.recordInvocation("WriteBundleToDir", []interface{}{arg1, arg2})
+	fake.writeBundleToDirMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeStringUtils) WriteBundleToDirCallCount() int {
+	fake.writeBundleToDirMutex.RLock()
+	defer fake.writeBundleToDirMutex.RUnlock()
+	return len(fake.writeBundleToDirArgsForCall)
+}
+
+func (fake *FakeStringUtils) WriteBundleToDirCalls(stub func(*utils_common.Bundle, string) error) {
+	fake.writeBundleToDirMutex.Lock()
+	defer fake.writeBundleToDirMutex.Unlock()
+	fake.WriteBundleToDirStub = stub
+}
+
+func (fake *FakeStringUtils) WriteBundleToDirArgsForCall(i int) (*utils_common.Bundle, string) {
+	fake.writeBundleToDirMutex.RLock()
+	defer fake.writeBundleToDirMutex.RUnlock()
+	argsForCall := fake.writeBundleToDirArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeStringUtils) WriteBundleToDirReturns(result1 error) {
+	fake.writeBundleToDirMutex.Lock()
+	defer fake.writeBundleToDirMutex.Unlock()
+	fake.WriteBundleToDirStub = nil
+	fake.writeBundleToDirReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeStringUtils) WriteBundleToDirReturnsOnCall(i int, result1 error) {
+	fake.writeBundleToDirMutex.Lock()
+	defer fake.writeBundleToDirMutex.Unlock()
+	fake.WriteBundleToDirStub = nil
+	if fake.writeBundleToDirReturnsOnCall == nil {
+		fake.writeBundleToDirReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.writeBundleToDirReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeStringUtils) WriteBundleToFile(arg1 *utils_common.Bundle, arg2 string) error {
+	fake.writeBundleToFileMutex.Lock()
+	ret, specificReturn := fake.writeBundleToFileReturnsOnCall[len(fake.writeBundleToFileArgsForCall)]
+	fake.writeBundleToFileArgsForCall = append(fake.writeBundleToFileArgsForCall, struct {
+		arg1 *utils_common.Bundle
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.WriteBundleToFileStub
+	fakeReturns := fake.writeBundleToFileReturns
+	fake.recordInvocation("WriteBundleToFile", []interface{}{arg1, arg2})
+	fake.writeBundleToFileMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeStringUtils) WriteBundleToFileCallCount() int {
+	fake.writeBundleToFileMutex.RLock()
+	defer fake.writeBundleToFileMutex.RUnlock()
+	return len(fake.writeBundleToFileArgsForCall)
+}
+
+func (fake *FakeStringUtils) WriteBundleToFileCalls(stub func(*utils_common.Bundle, string) error) {
+	fake.writeBundleToFileMutex.Lock()
+	defer fake.writeBundleToFileMutex.Unlock()
+	fake.WriteBundleToFileStub = stub
+}
+
+func (fake *FakeStringUtils) WriteBundleToFileArgsForCall(i int) (*utils_common.Bundle, string) {
+	fake.writeBundleToFileMutex.RLock()
+	defer fake.writeBundleToFileMutex.RUnlock()
+	argsForCall := fake.writeBundleToFileArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeStringUtils) WriteBundleToFileReturns(result1 error) {
+	fake.writeBundleToFileMutex.Lock()
+	defer fake.writeBundleToFileMutex.Unlock()
+	fake.WriteBundleToFileStub = nil
+	fake.writeBundleToFileReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeStringUtils) WriteBundleToFileReturnsOnCall(i int, result1 error) {
+	fake.writeBundleToFileMutex.Lock()
+	defer fake.writeBundleToFileMutex.Unlock()
+	fake.WriteBundleToFileStub = nil
+	if fake.writeBundleToFileReturnsOnCall == nil {
+		fake.writeBundleToFileReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.writeBundleToFileReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeStringUtils) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
-	fake.copyRootPathToClipboardMutex.RLock()
-	defer fake.copyRootPathToClipboardMutex.RUnlock()
+	fake.buildBundleMutex.RLock()
+	defer fake.buildBundleMutex.RUnlock()
+	fake.clipBundleMutex.RLock()
+	defer fake.clipBundleMutex.RUnlock()
+	fake.writeArchiveMutex.RLock()
+	defer fake.writeArchiveMutex.RUnlock()
+	fake.writeBundleMutex.RLock()
+	defer fake.writeBundleMutex.RUnlock()
+	fake.writeBundleToDirMutex.RLock()
+	defer fake.writeBundleToDirMutex.RUnlock()
+	fake.writeBundleToFileMutex.RLock()
+	defer fake.writeBundleToFileMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
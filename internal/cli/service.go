@@ -1,34 +1,90 @@
 package cli
 
 import (
+	"context"
 	"fmt"
+	"github.com/dembygenesis/local.tools/internal/models"
 	"github.com/dembygenesis/local.tools/internal/utils_common"
+	"io"
+	"os"
 )
 
 type Service struct {
-	stringUtils stringUtils
-	gptUtils    gptUtils
-	fileUtils   fileUtils
+	stringUtils  stringUtils
+	gptUtils     gptUtils
+	fileUtils    fileUtils
+	historyUtils historyUtils
+	stdout       io.Writer
 }
 
 func NewService(
 	stringUtils stringUtils,
 	gptUtils gptUtils,
 	fileUtils fileUtils,
+	historyUtils historyUtils,
 ) *Service {
 	return &Service{
 		stringUtils,
 		gptUtils,
 		fileUtils,
+		historyUtils,
+		os.Stdout,
 	}
 }
 
-func (s *Service) CopyToClipboard(root string, exclude []string) ([]string, error) {
-	files, err := s.stringUtils.CopyRootPathToClipboard(root, exclude)
+// ClipFileContents bundles the file contents of the root and routes
+// the bundle to stdout, a file, a directory (when split) or the clipboard
+// (the default). A dry run only builds the bundle.
+func (s *Service) ClipFileContents(ctx context.Context, opts *utils_common.ClipOptions) (*utils_common.ClipResult, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, fmt.Errorf("validate: %v", err)
+	}
+
+	bundle, err := s.stringUtils.BuildBundle(ctx, opts)
 	if err != nil {
-		return nil, fmt.Errorf("copy to clipboard: %v", err)
+		return nil, fmt.Errorf("build bundle: %v", err)
+	}
+	if bundle == nil {
+		return nil, models.ErrBundleNil
 	}
-	return files, nil
+
+	if opts.DryRun {
+		return bundle.Result, nil
+	}
+
+	switch {
+	case opts.Stdout:
+		stdout := s.stdout
+		if stdout == nil {
+			stdout = os.Stdout
+		}
+		err = s.stringUtils.WriteBundle(bundle, stdout)
+	case opts.OutFile != "":
+		err = s.stringUtils.WriteBundleToFile(bundle, opts.OutFile)
+	case opts.OutDir != "":
+		err = s.stringUtils.WriteBundleToDir(bundle, opts.OutDir)
+	default:
+		err = s.stringUtils.ClipBundle(bundle)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("write bundle: %v", err)
+	}
+
+	return bundle.Result, nil
+}
+
+// ArchiveFiles packages the files selected from the root into a zip
+// or tar.gz archive, with a manifest of the files.
+func (s *Service) ArchiveFiles(ctx context.Context, opts *utils_common.ClipOptions) (*utils_common.ArchiveResult, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, fmt.Errorf("validate: %v", err)
+	}
+
+	result, err := s.stringUtils.WriteArchive(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("write archive: %v", err)
+	}
+	return result, nil
 }
 
 func (s *Service) ClipCodingStandardsPreface() error {
@@ -50,3 +106,61 @@ func (s *Service) CopyDirToAnother(opts *utils_common.CopyOptions) error {
 	}
 	return nil
 }
+
+// PlanApplyBundle reads a bundle from the clipboard (or stdin) and returns
+// the changes it would make under the root, so they can be reviewed.
+func (s *Service) PlanApplyBundle(opts *utils_common.ApplyOptions, stdin io.Reader) (*utils_common.ApplyPlan, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, fmt.Errorf("validate: %v", err)
+	}
+
+	plan, err := s.fileUtils.PlanApply(opts, stdin)
+	if err != nil {
+		return nil, fmt.Errorf("plan apply: %v", err)
+	}
+	return plan, nil
+}
+
+func (s *Service) ApplyBundle(plan *utils_common.ApplyPlan) error {
+	err := s.fileUtils.Apply(plan)
+	if err != nil {
+		return fmt.Errorf("apply bundle: %v", err)
+	}
+	return nil
+}
+
+func (s *Service) ApplyPatch(opts *utils_common.PatchOptions, stdin io.Reader) (*utils_common.PatchResult, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, fmt.Errorf("validate: %v", err)
+	}
+
+	result, err := s.fileUtils.ApplyPatch(opts, stdin)
+	if err != nil {
+		return nil, fmt.Errorf("apply patch: %v", err)
+	}
+	return result, nil
+}
+
+func (s *Service) ListHistory() ([]utils_common.HistoryEntry, error) {
+	entries, err := s.historyUtils.List()
+	if err != nil {
+		return nil, fmt.Errorf("list history: %v", err)
+	}
+	return entries, nil
+}
+
+func (s *Service) ShowHistory(id int) (*utils_common.HistoryEntry, string, error) {
+	entry, content, err := s.historyUtils.Show(id)
+	if err != nil {
+		return nil, "", fmt.Errorf("show history: %v", err)
+	}
+	return entry, content, nil
+}
+
+func (s *Service) RestoreHistory(id int) (*utils_common.HistoryEntry, error) {
+	entry, err := s.historyUtils.Restore(id)
+	if err != nil {
+		return nil, fmt.Errorf("restore history: %v", err)
+	}
+	return entry, nil
+}
@@ -1,6 +1,8 @@
 package cli
 
 import (
+	"bytes"
+	"context"
 	"errors"
 	"github.com/dembygenesis/local.tools/internal/cli/clifakes"
 	"github.com/dembygenesis/local.tools/internal/utils_common"
@@ -8,27 +10,78 @@ import (
 	"testing"
 )
 
-func TestServices_CopyToClipboard_Success(t *testing.T) {
+func TestServices_ClipFileContents_Success(t *testing.T) {
 	mockStringUtils := clifakes.FakeStringUtils{}
 	mockGptUtils := clifakes.FakeGptUtils{}
 	mockFileUtils := clifakes.FakeFileUtils{}
 
+	mockStringUtils.BuildBundleReturns(&utils_common.Bundle{Result: &utils_common.ClipResult{}}, nil)
+
+	srv := Service{
+		stringUtils: &mockStringUtils,
+		gptUtils:    &mockGptUtils,
+		fileUtils:   &mockFileUtils,
+	}
+
+	_, err := srv.ClipFileContents(context.Background(), &utils_common.ClipOptions{Paths: []string{"."}})
+	require.NoError(t, err, "should have no error")
+	require.Equal(t, 1, mockStringUtils.ClipBundleCallCount(), "should default to the clipboard")
+}
+
+func TestServices_ClipFileContents_Stdout(t *testing.T) {
+	mockStringUtils := clifakes.FakeStringUtils{}
+	mockGptUtils := clifakes.FakeGptUtils{}
+	mockFileUtils := clifakes.FakeFileUtils{}
+
+	bundle := &utils_common.Bundle{Result: &utils_common.ClipResult{}}
+	mockStringUtils.BuildBundleReturns(bundle, nil)
+
+	stdout := &bytes.Buffer{}
+	srv := Service{
+		stringUtils: &mockStringUtils,
+		gptUtils:    &mockGptUtils,
+		fileUtils:   &mockFileUtils,
+		stdout:      stdout,
+	}
+
+	_, err := srv.ClipFileContents(context.Background(), &utils_common.ClipOptions{Paths: []string{"."}, Stdout: true})
+	require.NoError(t, err, "should have no error")
+	require.Equal(t, 0, mockStringUtils.ClipBundleCallCount())
+	require.Equal(t, 1, mockStringUtils.WriteBundleCallCount())
+
+	actualBundle, actualWriter := mockStringUtils.WriteBundleArgsForCall(0)
+	require.Equal(t, bundle, actualBundle)
+	require.Equal(t, stdout, actualWriter)
+}
+
+func TestServices_ClipFileContents_Out_File(t *testing.T) {
+	mockStringUtils := clifakes.FakeStringUtils{}
+	mockGptUtils := clifakes.FakeGptUtils{}
+	mockFileUtils := clifakes.FakeFileUtils{}
+
+	mockStringUtils.BuildBundleReturns(&utils_common.Bundle{Result: &utils_common.ClipResult{}}, nil)
+
 	srv := Service{
 		stringUtils: &mockStringUtils,
 		gptUtils:    &mockGptUtils,
 		fileUtils:   &mockFileUtils,
 	}
 
-	_, err := srv.CopyToClipboard(".", nil)
+	_, err := srv.ClipFileContents(context.Background(), &utils_common.ClipOptions{Paths: []string{"."}, OutFile: "bundle.txt"})
 	require.NoError(t, err, "should have no error")
+	require.Equal(t, 0, mockStringUtils.ClipBundleCallCount())
+	require.Equal(t, 1, mockStringUtils.WriteBundleToFileCallCount())
+
+	_, path := mockStringUtils.WriteBundleToFileArgsForCall(0)
+	require.Equal(t, "bundle.txt", path)
 }
 
-func TestServices_CopyToClipboard_Fail(t *testing.T) {
+func TestServices_ClipFileContents_Fail(t *testing.T) {
 	mockStringUtils := clifakes.FakeStringUtils{}
 	mockGptUtils := clifakes.FakeGptUtils{}
 	mockFileUtils := clifakes.FakeFileUtils{}
 
-	mockStringUtils.CopyRootPathToClipboardReturns(nil, errors.New("mock error"))
+	mockStringUtils.BuildBundleReturns(nil, errors.New("mock error"))
 
 	srv := Service{
 		stringUtils: &mockStringUtils,
@@ -36,10 +89,74 @@ func TestServices_CopyToClipboard_Fail(t *testing.T) {
 		fileUtils:   &mockFileUtils,
 	}
 
-	_, err := srv.CopyToClipboard(".", nil)
-	require.Error(t, err, "should have no error")
+	_, err := srv.ClipFileContents(context.Background(), &utils_common.ClipOptions{Paths: []string{"."}})
+	require.Error(t, err, "should have an error")
 	require.Contains(t, err.Error(), "mock error")
-	require.Contains(t, err.Error(), "copy to clipboard:")
+	require.Contains(t, err.Error(), "build bundle:")
+}
+
+func TestServices_ClipFileContents_Dry_Run(t *testing.T) {
+	mockStringUtils := clifakes.FakeStringUtils{}
+	mockGptUtils := clifakes.FakeGptUtils{}
+	mockFileUtils := clifakes.FakeFileUtils{}
+
+	result := &utils_common.ClipResult{Stats: []utils_common.FileStat{{Path: "a.go"}}}
+	mockStringUtils.BuildBundleReturns(&utils_common.Bundle{Result: result}, nil)
+
+	srv := Service{
+		stringUtils: &mockStringUtils,
+		gptUtils:    &mockGptUtils,
+		fileUtils:   &mockFileUtils,
+	}
+
+	actual, err := srv.ClipFileContents(context.Background(), &utils_common.ClipOptions{Paths: []string{"."}, DryRun: true, Sort: "size"})
+	require.NoError(t, err, "should have no error")
+	require.Equal(t, result, actual)
+	require.Equal(t, 0, mockStringUtils.ClipBundleCallCount(), "should not write the bundle")
+	require.Equal(t, 0, mockStringUtils.WriteBundleCallCount(), "should not write the bundle")
+	require.Equal(t, 0, mockStringUtils.WriteBundleToFileCallCount(), "should not write the bundle")
+}
+
+func TestServices_ClipFileContents_Sink_Fail(t *testing.T) {
+	mockStringUtils := clifakes.FakeStringUtils{}
+	mockGptUtils := clifakes.FakeGptUtils{}
+	mockFileUtils := clifakes.FakeFileUtils{}
+
+	mockStringUtils.BuildBundleReturns(&utils_common.Bundle{Result: &utils_common.ClipResult{}}, nil)
+	mockStringUtils.ClipBundleReturns(errors.New("mock error"))
+
+	srv := Service{
+		stringUtils: &mockStringUtils,
+		gptUtils:    &mockGptUtils,
+		fileUtils:   &mockFileUtils,
+	}
+
+	_, err := srv.ClipFileContents(context.Background(), &utils_common.ClipOptions{Paths: []string{"."}})
+	require.Error(t, err, "should have an error")
+	require.Contains(t, err.Error(), "mock error")
+	require.Contains(t, err.Error(), "write bundle:")
+}
+
+func TestServices_ClipFileContents_Validate_Fail(t *testing.T) {
+	mockStringUtils := clifakes.FakeStringUtils{}
+	mockGptUtils := clifakes.FakeGptUtils{}
+	mockFileUtils := clifakes.FakeFileUtils{}
+
+	srv := Service{
+		stringUtils: &mockStringUtils,
+		gptUtils:    &mockGptUtils,
+		fileUtils:   &mockFileUtils,
+	}
+
+	_, err := srv.ClipFileContents(context.Background(), &utils_common.ClipOptions{Paths: []string{"."}, Binary: "unknown"})
+	require.Error(t, err, "expected an error due to the unsupported binary policy")
+	require.Contains(t, err.Error(), "validate:")
+	require.Contains(t, err.Error(), "'unknown'")
+	require.Equal(t, 0, mockStringUtils.BuildBundleCallCount())
+
+	_, err = srv.ClipFileContents(context.Background(), &utils_common.ClipOptions{Paths: []string{"."}, Stdout: true, OutFile: "a.txt"})
+	require.Error(t, err, "expected an error due to conflicting sinks")
+	require.Contains(t, err.Error(), "cannot be used together")
 }
 
 func TestServices_ClipCodingStandardsPreface_Success(t *testing.T) {
@@ -61,11 +178,13 @@ func TestServices_New(t *testing.T) {
 	mockStringUtils := clifakes.FakeStringUtils{}
 	mockGptUtils := clifakes.FakeGptUtils{}
 	mockFileUtils := clifakes.FakeFileUtils{}
+	mockHistoryUtils := clifakes.FakeHistoryUtils{}
 
 	_ = NewService(
 		&mockStringUtils,
 		&mockGptUtils,
 		&mockFileUtils,
+		&mockHistoryUtils,
 	)
 }
 
@@ -157,3 +276,146 @@ func TestServices_CopyDirToAnother_Opts_Fail(t *testing.T) {
 
 	require.Error(t, err, "expected an error from copy operation")
 }
+
+func TestServices_PlanApplyBundle_Validate_Fail(t *testing.T) {
+	mockFileUtils := clifakes.FakeFileUtils{}
+
+	srv := Service{fileUtils: &mockFileUtils}
+
+	_, err := srv.PlanApplyBundle(&utils_common.ApplyOptions{}, nil)
+	require.Error(t, err, "expected an error due to the missing root")
+	require.Contains(t, err.Error(), "validate:")
+	require.Equal(t, 0, mockFileUtils.PlanApplyCallCount())
+}
+
+func TestServices_PlanApplyBundle_Success(t *testing.T) {
+	mockFileUtils := clifakes.FakeFileUtils{}
+	mockFileUtils.PlanApplyReturns(&utils_common.ApplyPlan{Root: "/root"}, nil)
+
+	srv := Service{fileUtils: &mockFileUtils}
+
+	plan, err := srv.PlanApplyBundle(&utils_common.ApplyOptions{Root: "."}, nil)
+	require.NoError(t, err, "should have no error")
+	require.Equal(t, "/root", plan.Root)
+}
+
+func TestServices_ApplyBundle_Fail(t *testing.T) {
+	mockFileUtils := clifakes.FakeFileUtils{}
+	mockFileUtils.ApplyReturns(errors.New("mock error"))
+
+	srv := Service{fileUtils: &mockFileUtils}
+
+	err := srv.ApplyBundle(&utils_common.ApplyPlan{})
+	require.Error(t, err, "should have an error")
+	require.Contains(t, err.Error(), "apply bundle:")
+	require.Contains(t, err.Error(), "mock error")
+}
+
+func TestServices_ApplyPatch_Validate_Fail(t *testing.T) {
+	mockFileUtils := clifakes.FakeFileUtils{}
+
+	srv := Service{fileUtils: &mockFileUtils}
+
+	_, err := srv.ApplyPatch(&utils_common.PatchOptions{}, nil)
+	require.Error(t, err, "expected an error due to the missing root")
+	require.Contains(t, err.Error(), "validate:")
+	require.Equal(t, 0, mockFileUtils.ApplyPatchCallCount())
+}
+
+func TestServices_ApplyPatch_Fail(t *testing.T) {
+	mockFileUtils := clifakes.FakeFileUtils{}
+	mockFileUtils.ApplyPatchReturns(nil, errors.New("mock error"))
+
+	srv := Service{fileUtils: &mockFileUtils}
+
+	_, err := srv.ApplyPatch(&utils_common.PatchOptions{Root: "."}, nil)
+	require.Error(t, err, "should have an error")
+	require.Contains(t, err.Error(), "apply patch:")
+	require.Contains(t, err.Error(), "mock error")
+}
+
+func TestServices_ListHistory_Success(t *testing.T) {
+	mockHistoryUtils := clifakes.FakeHistoryUtils{}
+	mockHistoryUtils.ListReturns([]utils_common.HistoryEntry{{ID: 1}}, nil)
+
+	srv := Service{historyUtils: &mockHistoryUtils}
+
+	entries, err := srv.ListHistory()
+	require.NoError(t, err, "should have no error")
+	require.Len(t, entries, 1)
+}
+
+func TestServices_ShowHistory_Fail(t *testing.T) {
+	mockHistoryUtils := clifakes.FakeHistoryUtils{}
+	mockHistoryUtils.ShowReturns(nil, "", errors.New("mock error"))
+
+	srv := Service{historyUtils: &mockHistoryUtils}
+
+	_, _, err := srv.ShowHistory(1)
+	require.Error(t, err, "should have an error")
+	require.Contains(t, err.Error(), "show history:")
+	require.Contains(t, err.Error(), "mock error")
+}
+
+func TestServices_RestoreHistory_Success(t *testing.T) {
+	mockHistoryUtils := clifakes.FakeHistoryUtils{}
+	mockHistoryUtils.RestoreReturns(&utils_common.HistoryEntry{ID: 3}, nil)
+
+	srv := Service{historyUtils: &mockHistoryUtils}
+
+	entry, err := srv.RestoreHistory(3)
+	require.NoError(t, err, "should have no error")
+	require.Equal(t, 3, entry.ID)
+	require.Equal(t, 3, mockHistoryUtils.RestoreArgsForCall(0))
+}
+
+func TestServices_ArchiveFiles_Success(t *testing.T) {
+	mockStringUtils := clifakes.FakeStringUtils{}
+	mockStringUtils.WriteArchiveReturns(&utils_common.ArchiveResult{Path: "out.zip"}, nil)
+
+	srv := Service{stringUtils: &mockStringUtils}
+
+	result, err := srv.ArchiveFiles(context.Background(), &utils_common.ClipOptions{Paths: []string{"."}, Archive: "out.zip"})
+	require.NoError(t, err, "should have no error")
+	require.Equal(t, "out.zip", result.Path)
+	require.Equal(t, 0, mockStringUtils.BuildBundleCallCount())
+}
+
+func TestServices_ArchiveFiles_Fail_Validate(t *testing.T) {
+	mockStringUtils := clifakes.FakeStringUtils{}
+
+	srv := Service{stringUtils: &mockStringUtils}
+
+	_, err := srv.ArchiveFiles(context.Background(), &utils_common.ClipOptions{Paths: []string{"."}, Archive: "out.rar"})
+	require.Error(t, err, "expected an unsupported archive error")
+	require.Contains(t, err.Error(), "validate:")
+	require.Equal(t, 0, mockStringUtils.WriteArchiveCallCount())
+}
+
+func TestServices_ClipFileContents_Out_Dir(t *testing.T) {
+	mockStringUtils := clifakes.FakeStringUtils{}
+
+	bundle := &utils_common.Bundle{Result: &utils_common.ClipResult{}}
+	mockStringUtils.BuildBundleReturns(bundle, nil)
+
+	srv := Service{stringUtils: &mockStringUtils}
+
+	_, err := srv.ClipFileContents(context.Background(), &utils_common.ClipOptions{Paths: []string{"."}, SplitBy: "dir", OutDir: "out"})
+	require.NoError(t, err, "should have no error")
+	require.Equal(t, 0, mockStringUtils.ClipBundleCallCount())
+	require.Equal(t, 1, mockStringUtils.WriteBundleToDirCallCount())
+
+	_, dir := mockStringUtils.WriteBundleToDirArgsForCall(0)
+	require.Equal(t, "out", dir)
+}
+
+func TestServices_ClipFileContents_Fail_Split_Without_Out_Dir(t *testing.T) {
+	mockStringUtils := clifakes.FakeStringUtils{}
+
+	srv := Service{stringUtils: &mockStringUtils}
+
+	_, err := srv.ClipFileContents(context.Background(), &utils_common.ClipOptions{Paths: []string{"."}, SplitBy: "dir"})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "validate:")
+	require.Equal(t, 0, mockStringUtils.BuildBundleCallCount())
+}
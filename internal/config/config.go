@@ -8,6 +8,7 @@ import (
 	"log"
 	"os"
 	"strings"
+	"time"
 )
 
 type MysqlDatabaseCredentials struct {
@@ -19,7 +20,22 @@ type MysqlDatabaseCredentials struct {
 }
 
 type CopyToClipboard struct {
-	Exclusions []string `json:"exclusions" mapstructure:"exclusions"`
+	Exclusions   []string               `json:"exclusions" mapstructure:"exclusions"`
+	Format       string                 `json:"format" mapstructure:"CLIP_FORMAT"`
+	Profiles     map[string]ClipProfile `json:"profiles" mapstructure:"-"`
+	ProfilesJSON string                 `json:"-" mapstructure:"CLIP_PROFILES"`
+	ProfilesFile string                 `json:"-" mapstructure:"CLIP_PROFILES_FILE"`
+}
+
+// ClipProfile is a named slice of a repository to clip, e.g "db" for the
+// database layer and its tests. Include and Exclude are added to the
+// command's globs, while Format and MaxTokens only apply when not given.
+type ClipProfile struct {
+	Root      string   `json:"root"`
+	Include   []string `json:"include"`
+	Exclude   []string `json:"exclude"`
+	Format    string   `json:"format" validate:"omitempty,oneof=plain markdown xml json"`
+	MaxTokens int      `json:"max_tokens" validate:"gte=0"`
 }
 
 func (c *CopyToClipboard) ParseExclusions(s string) error {
@@ -33,6 +49,63 @@ func (c *CopyToClipboard) ParseExclusions(s string) error {
 	return nil
 }
 
+// ParseProfiles reads the clip profiles from the "CLIP_PROFILES_FILE" JSON
+// file, then the inline "CLIP_PROFILES" JSON, the latter winning on names
+// defined in both.
+func (c *CopyToClipboard) ParseProfiles() error {
+	c.Profiles = make(map[string]ClipProfile)
+
+	sources := make([]string, 0, 2)
+	if c.ProfilesFile != "" {
+		data, err := os.ReadFile(c.ProfilesFile)
+		if err != nil {
+			return fmt.Errorf("read profiles file: %v", err)
+		}
+		sources = append(sources, string(data))
+	}
+	if strings.TrimSpace(c.ProfilesJSON) != "" {
+		sources = append(sources, c.ProfilesJSON)
+	}
+
+	for _, source := range sources {
+		profiles := make(map[string]ClipProfile)
+		if err := utils_common.DecodeToStruct(source, &profiles); err != nil {
+			return fmt.Errorf("profiles decode: %v", err)
+		}
+		for name, profile := range profiles {
+			if err := utils_common.ValidateStruct(&profile); err != nil {
+				return fmt.Errorf("profile '%s': %v", name, err)
+			}
+			c.Profiles[name] = profile
+		}
+	}
+	return nil
+}
+
+// ClipHistory is where and for how long every clipboard write is kept.
+type ClipHistory struct {
+	Dir        string        `json:"dir" mapstructure:"CLIP_HISTORY_DIR"`
+	MaxEntries int           `json:"max_entries" mapstructure:"CLIP_HISTORY_MAX_ENTRIES"`
+	MaxAge     time.Duration `json:"max_age" mapstructure:"CLIP_HISTORY_MAX_AGE"`
+}
+
+func (c *ClipHistory) setDefaults() error {
+	if c.Dir == "" {
+		dir, err := utils_common.DefaultHistoryDir()
+		if err != nil {
+			return fmt.Errorf("default dir: %v", err)
+		}
+		c.Dir = dir
+	}
+	if c.MaxEntries == 0 {
+		c.MaxEntries = defaultClipHistoryMaxEntries
+	}
+	if c.MaxAge == 0 {
+		c.MaxAge = defaultClipHistoryMaxAge
+	}
+	return nil
+}
+
 type FolderAToFolderB struct {
 	GenericExclusions []string `json:"generic_exclusions" mapstructure:"generic_exclusions"`
 }
@@ -51,6 +124,7 @@ func (c *FolderAToFolderB) ParseExclusions(s string) error {
 type Config struct {
 	FolderAToFolderB         FolderAToFolderB         `json:"folder_a_to_folder_b"`
 	CopyToClipboard          CopyToClipboard          `json:"copy_to_clipboard"`
+	ClipHistory              ClipHistory              `json:"clip_history"`
 	MysqlDatabaseCredentials MysqlDatabaseCredentials `json:"mysq_database_credentials"`
 }
 
@@ -84,6 +158,25 @@ func New(envFile string) (*Config, error) {
 		return &config, fmt.Errorf("unmarshal copy to clipboard: %v", err)
 	}
 
+	err = viper.Unmarshal(&config.CopyToClipboard)
+	if err != nil {
+		return &config, fmt.Errorf("error trying to unmarshal the copy to clipboard settings: %w", err)
+	}
+	if config.CopyToClipboard.Format == "" {
+		config.CopyToClipboard.Format = defaultClipFormat
+	}
+	if err = config.CopyToClipboard.ParseProfiles(); err != nil {
+		return &config, fmt.Errorf("clip profiles: %v", err)
+	}
+
+	err = viper.Unmarshal(&config.ClipHistory)
+	if err != nil {
+		return &config, fmt.Errorf("error trying to unmarshal the clip history settings: %w", err)
+	}
+	if err = config.ClipHistory.setDefaults(); err != nil {
+		return &config, fmt.Errorf("clip history defaults: %v", err)
+	}
+
 	if err = config.FolderAToFolderB.ParseExclusions(genericExclusions); err != nil {
 		return &config, fmt.Errorf("unmarshal transfer files: %v", err)
 	}
@@ -1,5 +1,9 @@
 package config
 
+import (
+	"time"
+)
+
 const genericExclusions = `
   [
     ".idea",
@@ -7,3 +11,13 @@ const genericExclusions = `
     ".git"
   ]
 `
+
+// defaultClipFormat is the clipboard output format used when "CLIP_FORMAT" is not set.
+const defaultClipFormat = "plain"
+
+const (
+	// defaultClipHistoryMaxEntries is the number of clips kept when "CLIP_HISTORY_MAX_ENTRIES" is not set.
+	defaultClipHistoryMaxEntries = 200
+	// defaultClipHistoryMaxAge is how long clips are kept when "CLIP_HISTORY_MAX_AGE" is not set.
+	defaultClipHistoryMaxAge = 30 * 24 * time.Hour
+)
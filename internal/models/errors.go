@@ -5,6 +5,10 @@ import "errors"
 var (
 	ErrConfigNil          = errors.New("config nil")
 	ErrRootMissing        = errors.New("missing root")
+	ErrBundleNil          = errors.New("bundle is nil")
+	ErrOutFileMissing     = errors.New("missing out file")
+	ErrOutDirMissing      = errors.New("missing out dir")
+	ErrHistoryIdInvalid   = errors.New("history id must be a positive number")
 	ErrContainerIdMissing = errors.New("error, missing container id")
 	ErrDatabaseNil        = errors.New("database is nil")
 	ErrTimeoutNil         = errors.New("timeout is nil")
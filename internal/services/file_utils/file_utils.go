@@ -5,17 +5,27 @@ import (
 	"github.com/dembygenesis/local.tools/internal/config"
 	"github.com/dembygenesis/local.tools/internal/models"
 	"github.com/dembygenesis/local.tools/internal/utils_common"
+	"io"
 )
 
 //go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 -generate
 
 type FileUtils interface {
 	CopyDirToAnother(opts *utils_common.CopyOptions) error
+	PlanApply(opts *utils_common.ApplyOptions, stdin io.Reader) (*utils_common.ApplyPlan, error)
+	Apply(plan *utils_common.ApplyPlan) error
+	ApplyPatch(opts *utils_common.PatchOptions, stdin io.Reader) (*utils_common.PatchResult, error)
 }
 
 //counterfeiter:generate . osLayer
 type osLayer interface {
 	CopyDirToAnother(opts *utils_common.CopyOptions) error
+	ReadClipboard() (string, error)
+	ParseBundle(text string) ([]utils_common.BundleFile, error)
+	PlanBundle(root string, files []utils_common.BundleFile) (*utils_common.ApplyPlan, error)
+	ApplyBundle(plan *utils_common.ApplyPlan) error
+	ParsePatch(text string) ([]utils_common.FilePatch, error)
+	ApplyPatch(root string, patches []utils_common.FilePatch, check bool) (*utils_common.PatchResult, error)
 }
 
 func New(conf *config.Config, osLayer osLayer) (FileUtils, error) {
@@ -50,3 +60,84 @@ func (g *fileUtils) CopyDirToAnother(opts *utils_common.CopyOptions) error {
 
 	return nil
 }
+
+// PlanApply reads a bundle from the clipboard (or stdin) and compares
+// its files against the ones under the root, without writing anything.
+func (g *fileUtils) PlanApply(opts *utils_common.ApplyOptions, stdin io.Reader) (*utils_common.ApplyPlan, error) {
+	if opts == nil {
+		return nil, fmt.Errorf("opts nil")
+	}
+
+	text, err := g.readInput(opts.Stdin, stdin)
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := g.osLayer.ParseBundle(text)
+	if err != nil {
+		return nil, fmt.Errorf("parse bundle: %v", err)
+	}
+
+	plan, err := g.osLayer.PlanBundle(opts.Root, files)
+	if err != nil {
+		return nil, fmt.Errorf("plan: %v", err)
+	}
+
+	return plan, nil
+}
+
+func (g *fileUtils) Apply(plan *utils_common.ApplyPlan) error {
+	if plan == nil {
+		return fmt.Errorf("plan nil")
+	}
+
+	err := g.osLayer.ApplyBundle(plan)
+	if err != nil {
+		return fmt.Errorf("os: %v", err)
+	}
+
+	return nil
+}
+
+// ApplyPatch reads a unified diff from the clipboard (or stdin),
+// and applies it under the root or only checks it applies.
+func (g *fileUtils) ApplyPatch(opts *utils_common.PatchOptions, stdin io.Reader) (*utils_common.PatchResult, error) {
+	if opts == nil {
+		return nil, fmt.Errorf("opts nil")
+	}
+
+	text, err := g.readInput(opts.Stdin, stdin)
+	if err != nil {
+		return nil, err
+	}
+
+	patches, err := g.osLayer.ParsePatch(text)
+	if err != nil {
+		return nil, fmt.Errorf("parse patch: %v", err)
+	}
+
+	result, err := g.osLayer.ApplyPatch(opts.Root, patches, opts.Check)
+	if err != nil {
+		return nil, fmt.Errorf("os: %v", err)
+	}
+
+	return result, nil
+}
+
+// readInput returns the text on stdin when fromStdin is set,
+// or the one on the clipboard otherwise.
+func (g *fileUtils) readInput(fromStdin bool, stdin io.Reader) (string, error) {
+	if fromStdin {
+		data, err := io.ReadAll(stdin)
+		if err != nil {
+			return "", fmt.Errorf("read stdin: %v", err)
+		}
+		return string(data), nil
+	}
+
+	text, err := g.osLayer.ReadClipboard()
+	if err != nil {
+		return "", fmt.Errorf("read clipboard: %v", err)
+	}
+	return text, nil
+}
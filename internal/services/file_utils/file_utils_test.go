@@ -6,6 +6,7 @@ import (
 	"github.com/dembygenesis/local.tools/internal/services/file_utils/file_utilsfakes"
 	"github.com/dembygenesis/local.tools/internal/utils_common"
 	"github.com/stretchr/testify/require"
+	"strings"
 	"testing"
 )
 
@@ -44,3 +45,84 @@ func Test_fileUtils_CopyDir_Success(t *testing.T) {
 	err := fakeFileUtils.CopyDirToAnother(&opts)
 	require.NoError(t, err, "expected opts has error")
 }
+
+func Test_fileUtils_PlanApply_Reads_Stdin(t *testing.T) {
+	conf := config.Config{}
+	fakeOsLayer := file_utilsfakes.FakeOsLayer{}
+	fakeOsLayer.PlanBundleReturns(&utils_common.ApplyPlan{Root: "root"}, nil)
+
+	fakeFileUtils, _ := New(&conf, &fakeOsLayer)
+
+	opts := utils_common.ApplyOptions{Root: "root", Stdin: true}
+	plan, err := fakeFileUtils.PlanApply(&opts, strings.NewReader("--- a.txt ---\n"))
+	require.NoError(t, err)
+	require.Equal(t, "root", plan.Root)
+
+	require.Equal(t, 0, fakeOsLayer.ReadClipboardCallCount())
+	require.Equal(t, "--- a.txt ---\n", fakeOsLayer.ParseBundleArgsForCall(0))
+}
+
+func Test_fileUtils_PlanApply_Fail_Clipboard(t *testing.T) {
+	conf := config.Config{}
+	fakeOsLayer := file_utilsfakes.FakeOsLayer{}
+	fakeOsLayer.ReadClipboardReturns("", errors.New("mock error"))
+
+	fakeFileUtils, _ := New(&conf, &fakeOsLayer)
+
+	_, err := fakeFileUtils.PlanApply(&utils_common.ApplyOptions{Root: "root"}, nil)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "read clipboard:")
+	require.Equal(t, 0, fakeOsLayer.ParseBundleCallCount())
+}
+
+func Test_fileUtils_PlanApply_Fail_Parse(t *testing.T) {
+	conf := config.Config{}
+	fakeOsLayer := file_utilsfakes.FakeOsLayer{}
+	fakeOsLayer.ParseBundleReturns(nil, utils_common.ErrNoBundleFiles)
+
+	fakeFileUtils, _ := New(&conf, &fakeOsLayer)
+
+	_, err := fakeFileUtils.PlanApply(&utils_common.ApplyOptions{Root: "root"}, nil)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "parse bundle:")
+	require.Equal(t, 0, fakeOsLayer.PlanBundleCallCount())
+}
+
+func Test_fileUtils_Apply_Fail_Nil_Plan(t *testing.T) {
+	conf := config.Config{}
+	fakeFileUtils, _ := New(&conf, &file_utilsfakes.FakeOsLayer{})
+
+	err := fakeFileUtils.Apply(nil)
+	require.Error(t, err, "expected plan nil error")
+}
+
+func Test_fileUtils_ApplyPatch_Passes_Check(t *testing.T) {
+	conf := config.Config{}
+	fakeOsLayer := file_utilsfakes.FakeOsLayer{}
+	fakeOsLayer.ReadClipboardReturns("--- a/x\n+++ b/x\n", nil)
+	fakeOsLayer.ApplyPatchReturns(&utils_common.PatchResult{Root: "root"}, nil)
+
+	fakeFileUtils, _ := New(&conf, &fakeOsLayer)
+
+	result, err := fakeFileUtils.ApplyPatch(&utils_common.PatchOptions{Root: "root", Check: true}, nil)
+	require.NoError(t, err)
+	require.Equal(t, "root", result.Root)
+
+	require.Equal(t, "--- a/x\n+++ b/x\n", fakeOsLayer.ParsePatchArgsForCall(0))
+	root, _, check := fakeOsLayer.ApplyPatchArgsForCall(0)
+	require.Equal(t, "root", root)
+	require.True(t, check)
+}
+
+func Test_fileUtils_ApplyPatch_Fail_Parse(t *testing.T) {
+	conf := config.Config{}
+	fakeOsLayer := file_utilsfakes.FakeOsLayer{}
+	fakeOsLayer.ParsePatchReturns(nil, utils_common.ErrNoPatchFiles)
+
+	fakeFileUtils, _ := New(&conf, &fakeOsLayer)
+
+	_, err := fakeFileUtils.ApplyPatch(&utils_common.PatchOptions{Root: "root"}, nil)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "parse patch:")
+	require.Equal(t, 0, fakeOsLayer.ApplyPatchCallCount())
+}
@@ -8,6 +8,32 @@ import (
 )
 
 type FakeOsLayer struct {
+	ApplyBundleStub        func(*utils_common.ApplyPlan) error
+	applyBundleMutex       sync.RWMutex
+	applyBundleArgsForCall []struct {
+		arg1 *utils_common.ApplyPlan
+	}
+	applyBundleReturns struct {
+		result1 error
+	}
+	applyBundleReturnsOnCall map[int]struct {
+		result1 error
+	}
+	ApplyPatchStub        func(string, []utils_common.FilePatch, bool) (*utils_common.PatchResult, error)
+	applyPatchMutex       sync.RWMutex
+	applyPatchArgsForCall []struct {
+		arg1 string
+		arg2 []utils_common.FilePatch
+		arg3 bool
+	}
+	applyPatchReturns struct {
+		result1 *utils_common.PatchResult
+		result2 error
+	}
+	applyPatchReturnsOnCall map[int]struct {
+		result1 *utils_common.PatchResult
+		result2 error
+	}
 	CopyDirToAnotherStub        func(*utils_common.CopyOptions) error
 	copyDirToAnotherMutex       sync.RWMutex
 	copyDirToAnotherArgsForCall []struct {
@@ -19,10 +45,194 @@ type FakeOsLayer struct {
 	copyDirToAnotherReturnsOnCall map[int]struct {
 		result1 error
 	}
+	ParseBundleStub        func(string) ([]utils_common.BundleFile, error)
+	parseBundleMutex       sync.RWMutex
+	parseBundleArgsForCall []struct {
+		arg1 string
+	}
+	parseBundleReturns struct {
+		result1 []utils_common.BundleFile
+		result2 error
+	}
+	parseBundleReturnsOnCall map[int]struct {
+		result1 []utils_common.BundleFile
+		result2 error
+	}
+	ParsePatchStub        func(string) ([]utils_common.FilePatch, error)
+	parsePatchMutex       sync.RWMutex
+	parsePatchArgsForCall []struct {
+		arg1 string
+	}
+	parsePatchReturns struct {
+		result1 []utils_common.FilePatch
+		result2 error
+	}
+	parsePatchReturnsOnCall map[int]struct {
+		result1 []utils_common.FilePatch
+		result2 error
+	}
+	PlanBundleStub        func(string, []utils_common.BundleFile) (*utils_common.ApplyPlan, error)
+	planBundleMutex       sync.RWMutex
+	planBundleArgsForCall []struct {
+		arg1 string
+		arg2 []utils_common.BundleFile
+	}
+	planBundleReturns struct {
+		result1 *utils_common.ApplyPlan
+		result2 error
+	}
+	planBundleReturnsOnCall map[int]struct {
+		result1 *utils_common.ApplyPlan
+		result2 error
+	}
+	ReadClipboardStub        func() (string, error)
+	readClipboardMutex       sync.RWMutex
+	readClipboardArgsForCall []struct {
+	}
+	readClipboardReturns struct {
+		result1 string
+		result2 error
+	}
+	readClipboardReturnsOnCall map[int]struct {
+		result1 string
+		result2 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
 
+func (fake *FakeOsLayer) ApplyBundle(arg1 *utils_common.ApplyPlan) error {
+	fake.applyBundleMutex.Lock()
+	ret, specificReturn := fake.applyBundleReturnsOnCall[len(fake.applyBundleArgsForCall)]
+	fake.applyBundleArgsForCall = append(fake.applyBundleArgsForCall, struct {
+		arg1 *utils_common.ApplyPlan
+	}{arg1})
+	stub := fake.ApplyBundleStub
+	fakeReturns := fake.applyBundleReturns
+	fake.recordInvocation("ApplyBundle", []interface{}{arg1})
+	fake.applyBundleMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeOsLayer) ApplyBundleCallCount() int {
+	fake.applyBundleMutex.RLock()
+	defer fake.applyBundleMutex.RUnlock()
+	return len(fake.applyBundleArgsForCall)
+}
+
+func (fake *FakeOsLayer) ApplyBundleCalls(stub func(*utils_common.ApplyPlan) error) {
+	fake.applyBundleMutex.Lock()
+	defer fake.applyBundleMutex.Unlock()
+	fake.ApplyBundleStub = stub
+}
+
+func (fake *FakeOsLayer) ApplyBundleArgsForCall(i int) *utils_common.ApplyPlan {
+	fake.applyBundleMutex.RLock()
+	defer fake.applyBundleMutex.RUnlock()
+	argsForCall := fake.applyBundleArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeOsLayer) ApplyBundleReturns(result1 error) {
+	fake.applyBundleMutex.Lock()
+	defer fake.applyBundleMutex.Unlock()
+	fake.ApplyBundleStub = nil
+	fake.applyBundleReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeOsLayer) ApplyBundleReturnsOnCall(i int, result1 error) {
+	fake.applyBundleMutex.Lock()
+	defer fake.applyBundleMutex.Unlock()
+	fake.ApplyBundleStub = nil
+	if fake.applyBundleReturnsOnCall == nil {
+		fake.applyBundleReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.applyBundleReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeOsLayer) ApplyPatch(arg1 string, arg2 []utils_common.FilePatch, arg3 bool) (*utils_common.PatchResult, error) {
+	var arg2Copy []utils_common.FilePatch
+	if arg2 != nil {
+		arg2Copy = make([]utils_common.FilePatch, len(arg2))
+		copy(arg2Copy, arg2)
+	}
+	fake.applyPatchMutex.Lock()
+	ret, specificReturn := fake.applyPatchReturnsOnCall[len(fake.applyPatchArgsForCall)]
+	fake.applyPatchArgsForCall = append(fake.applyPatchArgsForCall, struct {
+		arg1 string
+		arg2 []utils_common.FilePatch
+		arg3 bool
+	}{arg1, arg2Copy, arg3})
+	stub := fake.ApplyPatchStub
+	fakeReturns := fake.applyPatchReturns
+	fake.recordInvocation("ApplyPatch", []interface{}{arg1, arg2Copy, arg3})
+	fake.applyPatchMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeOsLayer) ApplyPatchCallCount() int {
+	fake.applyPatchMutex.RLock()
+	defer fake.applyPatchMutex.RUnlock()
+	return len(fake.applyPatchArgsForCall)
+}
+
+func (fake *FakeOsLayer) ApplyPatchCalls(stub func(string, []utils_common.FilePatch, bool) (*utils_common.PatchResult, error)) {
+	fake.applyPatchMutex.Lock()
+	defer fake.applyPatchMutex.Unlock()
+	fake.ApplyPatchStub = stub
+}
+
+func (fake *FakeOsLayer) ApplyPatchArgsForCall(i int) (string, []utils_common.FilePatch, bool) {
+	fake.applyPatchMutex.RLock()
+	defer fake.applyPatchMutex.RUnlock()
+	argsForCall := fake.applyPatchArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeOsLayer) ApplyPatchReturns(result1 *utils_common.PatchResult, result2 error) {
+	fake.applyPatchMutex.Lock()
+	defer fake.applyPatchMutex.Unlock()
+	fake.ApplyPatchStub = nil
+	fake.applyPatchReturns = struct {
+		result1 *utils_common.PatchResult
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeOsLayer) ApplyPatchReturnsOnCall(i int, result1 *utils_common.PatchResult, result2 error) {
+	fake.applyPatchMutex.Lock()
+	defer fake.applyPatchMutex.Unlock()
+	fake.ApplyPatchStub = nil
+	if fake.applyPatchReturnsOnCall == nil {
+		fake.applyPatchReturnsOnCall = make(map[int]struct {
+			result1 *utils_common.PatchResult
+			result2 error
+		})
+	}
+	fake.applyPatchReturnsOnCall[i] = struct {
+		result1 *utils_common.PatchResult
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeOsLayer) CopyDirToAnother(arg1 *utils_common.CopyOptions) error {
 	fake.copyDirToAnotherMutex.Lock()
 	ret, specificReturn := fake.copyDirToAnotherReturnsOnCall[len(fake.copyDirToAnotherArgsForCall)]
@@ -84,11 +294,277 @@ func (fake *FakeOsLayer) CopyDirToAnotherReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *FakeOsLayer) ParseBundle(arg1 string) ([]utils_common.BundleFile, error) {
+	fake.parseBundleMutex.Lock()
+	ret, specificReturn := fake.parseBundleReturnsOnCall[len(fake.parseBundleArgsForCall)]
+	fake.parseBundleArgsForCall = append(fake.parseBundleArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.ParseBundleStub
+	fakeReturns := fake.parseBundleReturns
+	fake.recordInvocation("ParseBundle", []interface{}{arg1})
+	fake.parseBundleMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeOsLayer) ParseBundleCallCount() int {
+	fake.parseBundleMutex.RLock()
+	defer fake.parseBundleMutex.RUnlock()
+	return len(fake.parseBundleArgsForCall)
+}
+
+func (fake *FakeOsLayer) ParseBundleCalls(stub func(string) ([]utils_common.BundleFile, error)) {
+	fake.parseBundleMutex.Lock()
+	defer fake.parseBundleMutex.Unlock()
+	fake.ParseBundleStub = stub
+}
+
+func (fake *FakeOsLayer) ParseBundleArgsForCall(i int) string {
+	fake.parseBundleMutex.RLock()
+	defer fake.parseBundleMutex.RUnlock()
+	argsForCall := fake.parseBundleArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeOsLayer) ParseBundleReturns(result1 []utils_common.BundleFile, result2 error) {
+	fake.parseBundleMutex.Lock()
+	defer fake.parseBundleMutex.Unlock()
+	fake.ParseBundleStub = nil
+	fake.parseBundleReturns = struct {
+		result1 []utils_common.BundleFile
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeOsLayer) ParseBundleReturnsOnCall(i int, result1 []utils_common.BundleFile, result2 error) {
+	fake.parseBundleMutex.Lock()
+	defer fake.parseBundleMutex.Unlock()
+	fake.ParseBundleStub = nil
+	if fake.parseBundleReturnsOnCall == nil {
+		fake.parseBundleReturnsOnCall = make(map[int]struct {
+			result1 []utils_common.BundleFile
+			result2 error
+		})
+	}
+	fake.parseBundleReturnsOnCall[i] = struct {
+		result1 []utils_common.BundleFile
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeOsLayer) ParsePatch(arg1 string) ([]utils_common.FilePatch, error) {
+	fake.parsePatchMutex.Lock()
+	ret, specificReturn := fake.parsePatchReturnsOnCall[len(fake.parsePatchArgsForCall)]
+	fake.parsePatchArgsForCall = append(fake.parsePatchArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.ParsePatchStub
+	fakeReturns := fake.parsePatchReturns
+	fake.recordInvocation("ParsePatch", []interface{}{arg1})
+	fake.parsePatchMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeOsLayer) ParsePatchCallCount() int {
+	fake.parsePatchMutex.RLock()
+	defer fake.parsePatchMutex.RUnlock()
+	return len(fake.parsePatchArgsForCall)
+}
+
+func (fake *FakeOsLayer) ParsePatchCalls(stub func(string) ([]utils_common.FilePatch, error)) {
+	fake.parsePatchMutex.Lock()
+	defer fake.parsePatchMutex.Unlock()
+	fake.ParsePatchStub = stub
+}
+
+func (fake *FakeOsLayer) ParsePatchArgsForCall(i int) string {
+	fake.parsePatchMutex.RLock()
+	defer fake.parsePatchMutex.RUnlock()
+	argsForCall := fake.parsePatchArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeOsLayer) ParsePatchReturns(result1 []utils_common.FilePatch, result2 error) {
+	fake.parsePatchMutex.Lock()
+	defer fake.parsePatchMutex.Unlock()
+	fake.ParsePatchStub = nil
+	fake.parsePatchReturns = struct {
+		result1 []utils_common.FilePatch
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeOsLayer) ParsePatchReturnsOnCall(i int, result1 []utils_common.FilePatch, result2 error) {
+	fake.parsePatchMutex.Lock()
+	defer fake.parsePatchMutex.Unlock()
+	fake.ParsePatchStub = nil
+	if fake.parsePatchReturnsOnCall == nil {
+		fake.parsePatchReturnsOnCall = make(map[int]struct {
+			result1 []utils_common.FilePatch
+			result2 error
+		})
+	}
+	fake.parsePatchReturnsOnCall[i] = struct {
+		result1 []utils_common.FilePatch
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeOsLayer) PlanBundle(arg1 string, arg2 []utils_common.BundleFile) (*utils_common.ApplyPlan, error) {
+	var arg2Copy []utils_common.BundleFile
+	if arg2 != nil {
+		arg2Copy = make([]utils_common.BundleFile, len(arg2))
+		copy(arg2Copy, arg2)
+	}
+	fake.planBundleMutex.Lock()
+	ret, specificReturn := fake.planBundleReturnsOnCall[len(fake.planBundleArgsForCall)]
+	fake.planBundleArgsForCall = append(fake.planBundleArgsForCall, struct {
+		arg1 string
+		arg2 []utils_common.BundleFile
+	}{arg1, arg2Copy})
+	stub := fake.PlanBundleStub
+	fakeReturns := fake.planBundleReturns
+	fake.recordInvocation("PlanBundle", []interface{}{arg1, arg2Copy})
+	fake.planBundleMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeOsLayer) PlanBundleCallCount() int {
+	fake.planBundleMutex.RLock()
+	defer fake.planBundleMutex.RUnlock()
+	return len(fake.planBundleArgsForCall)
+}
+
+func (fake *FakeOsLayer) PlanBundleCalls(stub func(string, []utils_common.BundleFile) (*utils_common.ApplyPlan, error)) {
+	fake.planBundleMutex.Lock()
+	defer fake.planBundleMutex.Unlock()
+	fake.PlanBundleStub = stub
+}
+
+func (fake *FakeOsLayer) PlanBundleArgsForCall(i int) (string, []utils_common.BundleFile) {
+	fake.planBundleMutex.RLock()
+	defer fake.planBundleMutex.RUnlock()
+	argsForCall := fake.planBundleArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeOsLayer) PlanBundleReturns(result1 *utils_common.ApplyPlan, result2 error) {
+	fake.planBundleMutex.Lock()
+	defer fake.planBundleMutex.Unlock()
+	fake.PlanBundleStub = nil
+	fake.planBundleReturns = struct {
+		result1 *utils_common.ApplyPlan
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeOsLayer) PlanBundleReturnsOnCall(i int, result1 *utils_common.ApplyPlan, result2 error) {
+	fake.planBundleMutex.Lock()
+	defer fake.planBundleMutex.Unlock()
+	fake.PlanBundleStub = nil
+	if fake.planBundleReturnsOnCall == nil {
+		fake.planBundleReturnsOnCall = make(map[int]struct {
+			result1 *utils_common.ApplyPlan
+			result2 error
+		})
+	}
+	fake.planBundleReturnsOnCall[i] = struct {
+		result1 *utils_common.ApplyPlan
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeOsLayer) ReadClipboard() (string, error) {
+	fake.readClipboardMutex.Lock()
+	ret, specificReturn := fake.readClipboardReturnsOnCall[len(fake.readClipboardArgsForCall)]
+	fake.readClipboardArgsForCall = append(fake.readClipboardArgsForCall, struct {
+	}{})
+	stub := fake.ReadClipboardStub
+	fakeReturns := fake.readClipboardReturns
+	fake.recordInvocation("ReadClipboard", []interface{}{})
+	fake.readClipboardMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeOsLayer) ReadClipboardCallCount() int {
+	fake.readClipboardMutex.RLock()
+	defer fake.readClipboardMutex.RUnlock()
+	return len(fake.readClipboardArgsForCall)
+}
+
+func (fake *FakeOsLayer) ReadClipboardCalls(stub func() (string, error)) {
+	fake.readClipboardMutex.Lock()
+	defer fake.readClipboardMutex.Unlock()
+	fake.ReadClipboardStub = stub
+}
+
+func (fake *FakeOsLayer) ReadClipboardReturns(result1 string, result2 error) {
+	fake.readClipboardMutex.Lock()
+	defer fake.readClipboardMutex.Unlock()
+	fake.ReadClipboardStub = nil
+	fake.readClipboardReturns = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeOsLayer) ReadClipboardReturnsOnCall(i int, result1 string, result2 error) {
+	fake.readClipboardMutex.Lock()
+	defer fake.readClipboardMutex.Unlock()
+	fake.ReadClipboardStub = nil
+	if fake.readClipboardReturnsOnCall == nil {
+		fake.readClipboardReturnsOnCall = make(map[int]struct {
+			result1 string
+			result2 error
+		})
+	}
+	fake.readClipboardReturnsOnCall[i] = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeOsLayer) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
+	fake.applyBundleMutex.RLock()
+	defer fake.applyBundleMutex.RUnlock()
+	fake.applyPatchMutex.RLock()
+	defer fake.applyPatchMutex.RUnlock()
 	fake.copyDirToAnotherMutex.RLock()
 	defer fake.copyDirToAnotherMutex.RUnlock()
+	fake.parseBundleMutex.RLock()
+	defer fake.parseBundleMutex.RUnlock()
+	fake.parsePatchMutex.RLock()
+	defer fake.parsePatchMutex.RUnlock()
+	fake.planBundleMutex.RLock()
+	defer fake.planBundleMutex.RUnlock()
+	fake.readClipboardMutex.RLock()
+	defer fake.readClipboardMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
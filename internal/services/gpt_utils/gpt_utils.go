@@ -2,22 +2,24 @@ package gpt_utils
 
 import (
 	"fmt"
-	"github.com/atotto/clipboard"
+	"github.com/dembygenesis/local.tools/internal/utils_common"
 )
 
 type GptUtils interface {
 	ClipCodingStandardsPreface() error
 }
 
-func New() GptUtils {
-	return &gptUtils{}
+// New returns the GPT utils, recording the clips into the history when given.
+func New(history *utils_common.History) GptUtils {
+	return &gptUtils{history: history}
 }
 
 type gptUtils struct {
+	history *utils_common.History
 }
 
 func (g *gptUtils) ClipCodingStandardsPreface() error {
-	if err := clipboard.WriteAll(preface); err != nil {
+	if err := utils_common.CopyToClipboard(preface, g.history); err != nil {
 		return fmt.Errorf("clip preface: %v", err)
 	}
 	return nil
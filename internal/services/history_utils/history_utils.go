@@ -0,0 +1,67 @@
+package history_utils
+
+import (
+	"fmt"
+	"github.com/dembygenesis/local.tools/internal/config"
+	"github.com/dembygenesis/local.tools/internal/models"
+	"github.com/dembygenesis/local.tools/internal/utils_common"
+)
+
+//go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 -generate
+
+type HistoryUtils interface {
+	List() ([]utils_common.HistoryEntry, error)
+	Show(id int) (*utils_common.HistoryEntry, string, error)
+	Restore(id int) (*utils_common.HistoryEntry, error)
+}
+
+//counterfeiter:generate . osLayer
+type osLayer interface {
+	List() ([]utils_common.HistoryEntry, error)
+	Get(id int) (*utils_common.HistoryEntry, string, error)
+	Restore(id int) (*utils_common.HistoryEntry, error)
+}
+
+func New(conf *config.Config, osLayer osLayer) (HistoryUtils, error) {
+	if conf == nil {
+		return nil, models.ErrConfigNil
+	}
+	return &historyUtils{conf, osLayer}, nil
+}
+
+type historyUtils struct {
+	conf    *config.Config
+	osLayer osLayer
+}
+
+func (h *historyUtils) List() ([]utils_common.HistoryEntry, error) {
+	entries, err := h.osLayer.List()
+	if err != nil {
+		return nil, fmt.Errorf("os: %v", err)
+	}
+	return entries, nil
+}
+
+func (h *historyUtils) Show(id int) (*utils_common.HistoryEntry, string, error) {
+	if id <= 0 {
+		return nil, "", models.ErrHistoryIdInvalid
+	}
+
+	entry, content, err := h.osLayer.Get(id)
+	if err != nil {
+		return nil, "", fmt.Errorf("os: %v", err)
+	}
+	return entry, content, nil
+}
+
+func (h *historyUtils) Restore(id int) (*utils_common.HistoryEntry, error) {
+	if id <= 0 {
+		return nil, models.ErrHistoryIdInvalid
+	}
+
+	entry, err := h.osLayer.Restore(id)
+	if err != nil {
+		return nil, fmt.Errorf("os: %v", err)
+	}
+	return entry, nil
+}
@@ -0,0 +1,59 @@
+package history_utils
+
+import (
+	"errors"
+	"github.com/dembygenesis/local.tools/internal/config"
+	"github.com/dembygenesis/local.tools/internal/models"
+	"github.com/dembygenesis/local.tools/internal/services/history_utils/history_utilsfakes"
+	"github.com/dembygenesis/local.tools/internal/utils_common"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func Test_New_Fail_Nil_Config(t *testing.T) {
+	_, err := New(nil, &history_utilsfakes.FakeOsLayer{})
+	require.ErrorIs(t, err, models.ErrConfigNil)
+}
+
+func Test_List_Fail_Os_Layer(t *testing.T) {
+	osLayer := history_utilsfakes.FakeOsLayer{}
+	osLayer.ListReturns(nil, errors.New("mock error"))
+
+	historyUtils, err := New(&config.Config{}, &osLayer)
+	require.NoError(t, err, "new")
+
+	_, err = historyUtils.List()
+	require.Error(t, err, "expected an os layer error")
+	require.Contains(t, err.Error(), "os:")
+	require.Contains(t, err.Error(), "mock error")
+}
+
+func Test_Show_Success(t *testing.T) {
+	osLayer := history_utilsfakes.FakeOsLayer{}
+	osLayer.GetReturns(&utils_common.HistoryEntry{ID: 2}, "content", nil)
+
+	historyUtils, err := New(&config.Config{}, &osLayer)
+	require.NoError(t, err, "new")
+
+	entry, content, err := historyUtils.Show(2)
+	require.NoError(t, err, "show")
+	require.Equal(t, 2, entry.ID)
+	require.Equal(t, "content", content)
+	require.Equal(t, 2, osLayer.GetArgsForCall(0))
+}
+
+func Test_Show_Restore_Fail_Invalid_Id(t *testing.T) {
+	osLayer := history_utilsfakes.FakeOsLayer{}
+
+	historyUtils, err := New(&config.Config{}, &osLayer)
+	require.NoError(t, err, "new")
+
+	_, _, err = historyUtils.Show(0)
+	require.ErrorIs(t, err, models.ErrHistoryIdInvalid)
+
+	_, err = historyUtils.Restore(-1)
+	require.ErrorIs(t, err, models.ErrHistoryIdInvalid)
+
+	require.Equal(t, 0, osLayer.GetCallCount())
+	require.Equal(t, 0, osLayer.RestoreCallCount())
+}
@@ -0,0 +1,268 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package history_utilsfakes
+
+import (
+	"sync"
+
+	"github.com/dembygenesis/local.tools/internal/utils_common"
+)
+
+type FakeOsLayer struct {
+	GetStub        func(int) (*utils_common.HistoryEntry, string, error)
+	getMutex       sync.RWMutex
+	getArgsForCall []struct {
+		arg1 int
+	}
+	getReturns struct {
+		result1 *utils_common.HistoryEntry
+		result2 string
+		result3 error
+	}
+	getReturnsOnCall map[int]struct {
+		result1 *utils_common.HistoryEntry
+		result2 string
+		result3 error
+	}
+	ListStub        func() ([]utils_common.HistoryEntry, error)
+	listMutex       sync.RWMutex
+	listArgsForCall []struct {
+	}
+	listReturns struct {
+		result1 []utils_common.HistoryEntry
+		result2 error
+	}
+	listReturnsOnCall map[int]struct {
+		result1 []utils_common.HistoryEntry
+		result2 error
+	}
+	RestoreStub        func(int) (*utils_common.HistoryEntry, error)
+	restoreMutex       sync.RWMutex
+	restoreArgsForCall []struct {
+		arg1 int
+	}
+	restoreReturns struct {
+		result1 *utils_common.HistoryEntry
+		result2 error
+	}
+	restoreReturnsOnCall map[int]struct {
+		result1 *utils_common.HistoryEntry
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeOsLayer) Get(arg1 int) (*utils_common.HistoryEntry, string, error) {
+	fake.getMutex.Lock()
+	ret, specificReturn := fake.getReturnsOnCall[len(fake.getArgsForCall)]
+	fake.getArgsForCall = append(fake.getArgsForCall, struct {
+		arg1 int
+	}{arg1})
+	stub := fake.GetStub
+	fakeReturns := fake.getReturns
+	fake.recordInvocation("Get", []interface{}{arg1})
+	fake.getMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2, ret.result3
+	}
+	return fakeReturns.result1, fakeReturns.result2, fakeReturns.result3
+}
+
+func (fake *FakeOsLayer) GetCallCount() int {
+	fake.getMutex.RLock()
+	defer fake.getMutex.RUnlock()
+	return len(fake.getArgsForCall)
+}
+
+func (fake *FakeOsLayer) GetCalls(stub func(int) (*utils_common.HistoryEntry, string, error)) {
+	fake.getMutex.Lock()
+	defer fake.getMutex.Unlock()
+	fake.GetStub = stub
+}
+
+func (fake *FakeOsLayer) GetArgsForCall(i int) int {
+	fake.getMutex.RLock()
+	defer fake.getMutex.RUnlock()
+	argsForCall := fake.getArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeOsLayer) GetReturns(result1 *utils_common.HistoryEntry, result2 string, result3 error) {
+	fake.getMutex.Lock()
+	defer fake.getMutex.Unlock()
+	fake.GetStub = nil
+	fake.getReturns = struct {
+		result1 *utils_common.HistoryEntry
+		result2 string
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeOsLayer) GetReturnsOnCall(i int, result1 *utils_common.HistoryEntry, result2 string, result3 error) {
+	fake.getMutex.Lock()
+	defer fake.getMutex.Unlock()
+	fake.GetStub = nil
+	if fake.getReturnsOnCall == nil {
+		fake.getReturnsOnCall = make(map[int]struct {
+			result1 *utils_common.HistoryEntry
+			result2 string
+			result3 error
+		})
+	}
+	fake.getReturnsOnCall[i] = struct {
+		result1 *utils_common.HistoryEntry
+		result2 string
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeOsLayer) List() ([]utils_common.HistoryEntry, error) {
+	fake.listMutex.Lock()
+	ret, specificReturn := fake.listReturnsOnCall[len(fake.listArgsForCall)]
+	fake.listArgsForCall = append(fake.listArgsForCall, struct {
+	}{})
+	stub := fake.ListStub
+	fakeReturns := fake.listReturns
+	fake.recordInvocation("List", []interface{}{})
+	fake.listMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeOsLayer) ListCallCount() int {
+	fake.listMutex.RLock()
+	defer fake.listMutex.RUnlock()
+	return len(fake.listArgsForCall)
+}
+
+func (fake *FakeOsLayer) ListCalls(stub func() ([]utils_common.HistoryEntry, error)) {
+	fake.listMutex.Lock()
+	defer fake.listMutex.Unlock()
+	fake.ListStub = stub
+}
+
+func (fake *FakeOsLayer) ListReturns(result1 []utils_common.HistoryEntry, result2 error) {
+	fake.listMutex.Lock()
+	defer fake.listMutex.Unlock()
+	fake.ListStub = nil
+	fake.listReturns = struct {
+		result1 []utils_common.HistoryEntry
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeOsLayer) ListReturnsOnCall(i int, result1 []utils_common.HistoryEntry, result2 error) {
+	fake.listMutex.Lock()
+	defer fake.listMutex.Unlock()
+	fake.ListStub = nil
+	if fake.listReturnsOnCall == nil {
+		fake.listReturnsOnCall = make(map[int]struct {
+			result1 []utils_common.HistoryEntry
+			result2 error
+		})
+	}
+	fake.listReturnsOnCall[i] = struct {
+		result1 []utils_common.HistoryEntry
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeOsLayer) Restore(arg1 int) (*utils_common.HistoryEntry, error) {
+	fake.restoreMutex.Lock()
+	ret, specificReturn := fake.restoreReturnsOnCall[len(fake.restoreArgsForCall)]
+	fake.restoreArgsForCall = append(fake.restoreArgsForCall, struct {
+		arg1 int
+	}{arg1})
+	stub := fake.RestoreStub
+	fakeReturns := fake.restoreReturns
+	fake.recordInvocation("Restore", []interface{}{arg1})
+	fake.restoreMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeOsLayer) RestoreCallCount() int {
+	fake.restoreMutex.RLock()
+	defer fake.restoreMutex.RUnlock()
+	return len(fake.restoreArgsForCall)
+}
+
+func (fake *FakeOsLayer) RestoreCalls(stub func(int) (*utils_common.HistoryEntry, error)) {
+	fake.restoreMutex.Lock()
+	defer fake.restoreMutex.Unlock()
+	fake.RestoreStub = stub
+}
+
+func (fake *FakeOsLayer) RestoreArgsForCall(i int) int {
+	fake.restoreMutex.RLock()
+	defer fake.restoreMutex.RUnlock()
+	argsForCall := fake.restoreArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeOsLayer) RestoreReturns(result1 *utils_common.HistoryEntry, result2 error) {
+	fake.restoreMutex.Lock()
+	defer fake.restoreMutex.Unlock()
+	fake.RestoreStub = nil
+	fake.restoreReturns = struct {
+		result1 *utils_common.HistoryEntry
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeOsLayer) RestoreReturnsOnCall(i int, result1 *utils_common.HistoryEntry, result2 error) {
+	fake.restoreMutex.Lock()
+	defer fake.restoreMutex.Unlock()
+	fake.RestoreStub = nil
+	if fake.restoreReturnsOnCall == nil {
+		fake.restoreReturnsOnCall = make(map[int]struct {
+			result1 *utils_common.HistoryEntry
+			result2 error
+		})
+	}
+	fake.restoreReturnsOnCall[i] = struct {
+		result1 *utils_common.HistoryEntry
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeOsLayer) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.getMutex.RLock()
+	defer fake.getMutex.RUnlock()
+	fake.listMutex.RLock()
+	defer fake.listMutex.RUnlock()
+	fake.restoreMutex.RLock()
+	defer fake.restoreMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeOsLayer) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
@@ -1,21 +1,35 @@
 package string_utils
 
 import (
+	"context"
 	"fmt"
 	"github.com/dembygenesis/local.tools/internal/config"
 	"github.com/dembygenesis/local.tools/internal/models"
+	"github.com/dembygenesis/local.tools/internal/utils_common"
+	"io"
+	"sort"
 	"strings"
 )
 
 //go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 -generate
 
 type StringUtils interface {
-	CopyRootPathToClipboard(root string, exclude []string) ([]string, error)
+	BuildBundle(ctx context.Context, opts *utils_common.ClipOptions) (*utils_common.Bundle, error)
+	ClipBundle(bundle *utils_common.Bundle) error
+	WriteBundle(bundle *utils_common.Bundle, w io.Writer) error
+	WriteBundleToFile(bundle *utils_common.Bundle, path string) error
+	WriteBundleToDir(bundle *utils_common.Bundle, dir string) error
+	WriteArchive(ctx context.Context, opts *utils_common.ClipOptions) (*utils_common.ArchiveResult, error)
 }
 
 //counterfeiter:generate . osLayer
 type osLayer interface {
-	CopyRootPathToClipboard(root string, exclude []string) ([]string, error)
+	BuildBundle(ctx context.Context, opts *utils_common.ClipOptions) (*utils_common.Bundle, error)
+	ClipBundle(bundle *utils_common.Bundle) error
+	WriteBundle(bundle *utils_common.Bundle, w io.Writer) error
+	WriteBundleToFile(bundle *utils_common.Bundle, path string) error
+	WriteBundleToDir(bundle *utils_common.Bundle, dir string) error
+	WriteArchive(ctx context.Context, opts *utils_common.ClipOptions) (*utils_common.ArchiveResult, error)
 }
 
 func New(conf *config.Config, osLayer osLayer) (StringUtils, error) {
@@ -30,24 +44,191 @@ type stringUtils struct {
 	osLayer osLayer
 }
 
-func (s *stringUtils) CopyRootPathToClipboard(root string, exclude []string) ([]string, error) {
-	root = strings.TrimSpace(root)
-	if root == "" {
-		return nil, models.ErrRootMissing
+func (s *stringUtils) BuildBundle(ctx context.Context, opts *utils_common.ClipOptions) (*utils_common.Bundle, error) {
+	if opts == nil {
+		return nil, fmt.Errorf("opts nil")
 	}
 
-	if exclude == nil {
-		exclude = make([]string, 0)
+	if err := s.prepareOptions(opts); err != nil {
+		return nil, err
 	}
 
-	for _, exclusion := range s.conf.CopyToClipboard.Exclusions {
-		exclude = append(exclude, exclusion)
+	bundle, err := s.osLayer.BuildBundle(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("os: %v", err)
+	}
+
+	return bundle, nil
+}
+
+func (s *stringUtils) WriteBundleToDir(bundle *utils_common.Bundle, dir string) error {
+	if bundle == nil {
+		return models.ErrBundleNil
+	}
+
+	dir = strings.TrimSpace(dir)
+	if dir == "" {
+		return models.ErrOutDirMissing
+	}
+
+	if err := s.osLayer.WriteBundleToDir(bundle, dir); err != nil {
+		return fmt.Errorf("os: %v", err)
+	}
+
+	return nil
+}
+
+// WriteArchive packages the files selected by the opts into their archive,
+// selecting them exactly as BuildBundle does.
+func (s *stringUtils) WriteArchive(ctx context.Context, opts *utils_common.ClipOptions) (*utils_common.ArchiveResult, error) {
+	if opts == nil {
+		return nil, fmt.Errorf("opts nil")
+	}
+
+	opts.Archive = strings.TrimSpace(opts.Archive)
+	if opts.Archive == "" {
+		return nil, models.ErrOutFileMissing
 	}
 
-	files, err := s.osLayer.CopyRootPathToClipboard(root, exclude)
+	if err := s.prepareOptions(opts); err != nil {
+		return nil, err
+	}
+
+	result, err := s.osLayer.WriteArchive(ctx, opts)
 	if err != nil {
 		return nil, fmt.Errorf("os: %v", err)
 	}
 
-	return files, nil
+	return result, nil
+}
+
+// prepareOptions applies the profile, the configured exclusions and the
+// format to the opts, then drops their blank paths and patterns. The opts
+// are validated again once merged, as a profile's root, format or token
+// budget can conflict with the flags.
+func (s *stringUtils) prepareOptions(opts *utils_common.ClipOptions) error {
+	if err := s.applyProfile(opts); err != nil {
+		return err
+	}
+
+	opts.Paths = trimPatterns(opts.Paths)
+	opts.GoPackages = trimPatterns(opts.GoPackages)
+	if len(opts.Paths) == 0 && len(opts.GoPackages) == 0 {
+		return models.ErrRootMissing
+	}
+
+	if opts.Exclusions == nil {
+		opts.Exclusions = make([]string, 0)
+	}
+
+	for _, exclusion := range s.conf.CopyToClipboard.Exclusions {
+		opts.Exclusions = append(opts.Exclusions, exclusion)
+	}
+
+	opts.Include = trimPatterns(opts.Include)
+	opts.Exclude = trimPatterns(opts.Exclude)
+
+	if opts.Format == "" {
+		opts.Format = s.conf.CopyToClipboard.Format
+	}
+
+	if err := opts.Validate(); err != nil {
+		return fmt.Errorf("validate: %v", err)
+	}
+
+	return nil
+}
+
+func (s *stringUtils) ClipBundle(bundle *utils_common.Bundle) error {
+	if bundle == nil {
+		return models.ErrBundleNil
+	}
+
+	if err := s.osLayer.ClipBundle(bundle); err != nil {
+		return fmt.Errorf("os: %v", err)
+	}
+
+	return nil
+}
+
+func (s *stringUtils) WriteBundle(bundle *utils_common.Bundle, w io.Writer) error {
+	if bundle == nil {
+		return models.ErrBundleNil
+	}
+
+	if err := s.osLayer.WriteBundle(bundle, w); err != nil {
+		return fmt.Errorf("os: %v", err)
+	}
+
+	return nil
+}
+
+func (s *stringUtils) WriteBundleToFile(bundle *utils_common.Bundle, path string) error {
+	if bundle == nil {
+		return models.ErrBundleNil
+	}
+
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return models.ErrOutFileMissing
+	}
+
+	if err := s.osLayer.WriteBundleToFile(bundle, path); err != nil {
+		return fmt.Errorf("os: %v", err)
+	}
+
+	return nil
+}
+
+// applyProfile merges the named clip profile into the opts. The profile's root
+// is only used when no paths are given and its format and token budget
+// only when the opts leave them unset.
+func (s *stringUtils) applyProfile(opts *utils_common.ClipOptions) error {
+	name := strings.TrimSpace(opts.Profile)
+	if name == "" {
+		return nil
+	}
+
+	profile, ok := s.conf.CopyToClipboard.Profiles[name]
+	if !ok {
+		names := make([]string, 0, len(s.conf.CopyToClipboard.Profiles))
+		for known := range s.conf.CopyToClipboard.Profiles {
+			names = append(names, known)
+		}
+		if len(names) == 0 {
+			return fmt.Errorf("unknown profile '%s', no profiles are configured (see CLIP_PROFILES)", name)
+		}
+		sort.Strings(names)
+		return fmt.Errorf("unknown profile '%s', expected one of: %s", name, strings.Join(names, ", "))
+	}
+
+	if len(trimPatterns(opts.Paths)) == 0 && len(trimPatterns(opts.GoPackages)) == 0 {
+		root := strings.TrimSpace(profile.Root)
+		if root == "" {
+			root = "."
+		}
+		opts.Paths = []string{root}
+	}
+	opts.Include = append(append([]string{}, profile.Include...), opts.Include...)
+	opts.Exclude = append(append([]string{}, profile.Exclude...), opts.Exclude...)
+	if opts.Format == "" {
+		opts.Format = profile.Format
+	}
+	if opts.MaxTokens == 0 {
+		opts.MaxTokens = profile.MaxTokens
+	}
+
+	return nil
+}
+
+// trimPatterns drops blank glob patterns (or paths) and surrounding spaces.
+func trimPatterns(patterns []string) []string {
+	trimmed := make([]string, 0, len(patterns))
+	for _, pattern := range patterns {
+		pattern = strings.TrimSpace(pattern)
+		if pattern != "" {
+			trimmed = append(trimmed, pattern)
+		}
+	}
+	return trimmed
 }
@@ -1,9 +1,12 @@
 package string_utils
 
 import (
+	"context"
 	"errors"
 	"github.com/dembygenesis/local.tools/internal/cli/clifakes"
 	"github.com/dembygenesis/local.tools/internal/config"
+	"github.com/dembygenesis/local.tools/internal/models"
+	"github.com/dembygenesis/local.tools/internal/utils_common"
 	"github.com/stretchr/testify/require"
 	"testing"
 )
@@ -12,38 +15,242 @@ func Test_New_Success(t *testing.T) {
 	conf := config.Config{}
 	osLayer := clifakes.FakeStringUtils{}
 
-	osLayer.CopyRootPathToClipboardReturns(nil, nil)
+	osLayer.BuildBundleReturns(nil, nil)
 
 	_, err := New(&conf, &osLayer)
 	require.NoError(t, err, "config error")
 }
 
-func Test_CopyRootPathToClipboard_Success(t *testing.T) {
+func Test_BuildBundle_Success(t *testing.T) {
 	conf := config.Config{}
 	conf.CopyToClipboard = config.CopyToClipboard{
 		Exclusions: []string{"ab", "cd"},
+		Format:     "markdown",
 	}
 	osLayer := clifakes.FakeStringUtils{}
 
-	osLayer.CopyRootPathToClipboardReturns(nil, nil)
+	osLayer.BuildBundleReturns(nil, nil)
 
 	fakeStringUtils, err := New(&conf, &osLayer)
 	require.NoError(t, err, "config error")
 
-	_, err = fakeStringUtils.CopyRootPathToClipboard("test", nil)
+	_, err = fakeStringUtils.BuildBundle(context.Background(), &utils_common.ClipOptions{Paths: []string{"test"}})
 	require.NoError(t, err, "no error expected")
+
+	_, opts := osLayer.BuildBundleArgsForCall(0)
+	require.Equal(t, []string{"ab", "cd"}, opts.Exclusions, "config exclusions should be merged")
+	require.Equal(t, "markdown", opts.Format, "config format should be the default")
 }
 
-func Test_CopyRootPathToClipboard_Fail_Empty_Root(t *testing.T) {
+func Test_BuildBundle_Format_Flag_Wins(t *testing.T) {
 	conf := config.Config{}
+	conf.CopyToClipboard = config.CopyToClipboard{
+		Format: "markdown",
+	}
 	osLayer := clifakes.FakeStringUtils{}
 
-	osLayer.CopyRootPathToClipboardReturns(nil, errors.New("mock error"))
+	fakeStringUtils, err := New(&conf, &osLayer)
+	require.NoError(t, err, "config error")
+
+	_, err = fakeStringUtils.BuildBundle(context.Background(), &utils_common.ClipOptions{Paths: []string{"test"}, Format: "xml"})
+	require.NoError(t, err, "no error expected")
+
+	_, opts := osLayer.BuildBundleArgsForCall(0)
+	require.Equal(t, "xml", opts.Format)
+}
+
+func Test_BuildBundle_Fail_Nil_Opts(t *testing.T) {
+	conf := config.Config{}
+	osLayer := clifakes.FakeStringUtils{}
 
 	fakeStringUtils, err := New(&conf, &osLayer)
 	require.NoError(t, err, "config error")
 
-	_, err = fakeStringUtils.CopyRootPathToClipboard("test", nil)
+	_, err = fakeStringUtils.BuildBundle(context.Background(), nil)
+	require.Error(t, err, "error expected")
+}
+
+func Test_BuildBundle_Fail_Missing_Root(t *testing.T) {
+	conf := config.Config{}
+	osLayer := clifakes.FakeStringUtils{}
+
+	fakeStringUtils, err := New(&conf, &osLayer)
+	require.NoError(t, err, "config error")
+
+	_, err = fakeStringUtils.BuildBundle(context.Background(), &utils_common.ClipOptions{Paths: []string{"  "}})
+	require.ErrorIs(t, err, models.ErrRootMissing)
+}
+
+func Test_BuildBundle_Fail_Empty_Root(t *testing.T) {
+	conf := config.Config{}
+	osLayer := clifakes.FakeStringUtils{}
+
+	osLayer.BuildBundleReturns(nil, errors.New("mock error"))
+
+	fakeStringUtils, err := New(&conf, &osLayer)
+	require.NoError(t, err, "config error")
+
+	_, err = fakeStringUtils.BuildBundle(context.Background(), &utils_common.ClipOptions{Paths: []string{"test"}})
 	require.Error(t, err, "error expected")
 	require.Contains(t, err.Error(), "os:")
 }
+
+func Test_WriteBundle_Fail_Nil_Bundle(t *testing.T) {
+	conf := config.Config{}
+	osLayer := clifakes.FakeStringUtils{}
+
+	fakeStringUtils, err := New(&conf, &osLayer)
+	require.NoError(t, err, "config error")
+
+	require.ErrorIs(t, fakeStringUtils.ClipBundle(nil), models.ErrBundleNil)
+	require.ErrorIs(t, fakeStringUtils.WriteBundle(nil, nil), models.ErrBundleNil)
+	require.ErrorIs(t, fakeStringUtils.WriteBundleToFile(nil, "a.txt"), models.ErrBundleNil)
+}
+
+func Test_WriteBundleToFile_Fail_Missing_Path(t *testing.T) {
+	conf := config.Config{}
+	osLayer := clifakes.FakeStringUtils{}
+
+	fakeStringUtils, err := New(&conf, &osLayer)
+	require.NoError(t, err, "config error")
+
+	err = fakeStringUtils.WriteBundleToFile(&utils_common.Bundle{}, " ")
+	require.ErrorIs(t, err, models.ErrOutFileMissing)
+}
+
+func Test_WriteBundleToFile_Fail_Os_Layer(t *testing.T) {
+	conf := config.Config{}
+	osLayer := clifakes.FakeStringUtils{}
+
+	osLayer.WriteBundleToFileReturns(errors.New("mock error"))
+
+	fakeStringUtils, err := New(&conf, &osLayer)
+	require.NoError(t, err, "config error")
+
+	err = fakeStringUtils.WriteBundleToFile(&utils_common.Bundle{}, "a.txt")
+	require.Error(t, err, "error expected")
+	require.Contains(t, err.Error(), "os:")
+}
+
+func Test_BuildBundle_Trims_Patterns(t *testing.T) {
+	conf := config.Config{}
+	osLayer := clifakes.FakeStringUtils{}
+
+	fakeStringUtils, err := New(&conf, &osLayer)
+	require.NoError(t, err, "config error")
+
+	_, err = fakeStringUtils.BuildBundle(context.Background(), &utils_common.ClipOptions{
+		Paths:   []string{"test"},
+		Include: []string{" **/*.go ", ""},
+		Exclude: []string{"  "},
+	})
+	require.NoError(t, err, "no error expected")
+
+	_, opts := osLayer.BuildBundleArgsForCall(0)
+	require.Equal(t, []string{"**/*.go"}, opts.Include)
+	require.Empty(t, opts.Exclude)
+}
+
+func Test_BuildBundle_Applies_Profile(t *testing.T) {
+	conf := config.Config{}
+	conf.CopyToClipboard = config.CopyToClipboard{
+		Format: "plain",
+		Profiles: map[string]config.ClipProfile{
+			"db": {
+				Root:      "internal/persistence",
+				Include:   []string{"**/*.go"},
+				Exclude:   []string{"**/fakes/**"},
+				Format:    "markdown",
+				MaxTokens: 8000,
+			},
+		},
+	}
+	osLayer := clifakes.FakeStringUtils{}
+
+	fakeStringUtils, err := New(&conf, &osLayer)
+	require.NoError(t, err, "config error")
+
+	_, err = fakeStringUtils.BuildBundle(context.Background(), &utils_common.ClipOptions{
+		Profile: "db",
+		Exclude: []string{"**/*_test.go"},
+		Format:  "xml",
+	})
+	require.NoError(t, err, "no error expected")
+
+	_, opts := osLayer.BuildBundleArgsForCall(0)
+	require.Equal(t, []string{"internal/persistence"}, opts.Paths)
+	require.Equal(t, []string{"**/*.go"}, opts.Include)
+	require.Equal(t, []string{"**/fakes/**", "**/*_test.go"}, opts.Exclude)
+	require.Equal(t, "xml", opts.Format, "the flag should win over the profile")
+	require.Equal(t, 8000, opts.MaxTokens)
+}
+
+func Test_BuildBundle_Fail_Unknown_Profile(t *testing.T) {
+	conf := config.Config{}
+	conf.CopyToClipboard = config.CopyToClipboard{
+		Profiles: map[string]config.ClipProfile{"db": {}, "api": {}},
+	}
+	osLayer := clifakes.FakeStringUtils{}
+
+	fakeStringUtils, err := New(&conf, &osLayer)
+	require.NoError(t, err, "config error")
+
+	_, err = fakeStringUtils.BuildBundle(context.Background(), &utils_common.ClipOptions{Profile: "web"})
+	require.Error(t, err, "error expected")
+	require.Contains(t, err.Error(), "api, db")
+	require.Equal(t, 0, osLayer.BuildBundleCallCount())
+}
+
+func Test_BuildBundle_Fail_Profile_Conflicts_With_Flags(t *testing.T) {
+	conf := config.Config{}
+	conf.CopyToClipboard = config.CopyToClipboard{
+		Profiles: map[string]config.ClipProfile{"db": {Root: "internal/persistence", MaxTokens: 8000}},
+	}
+	osLayer := clifakes.FakeStringUtils{}
+
+	fakeStringUtils, err := New(&conf, &osLayer)
+	require.NoError(t, err, "config error")
+
+	opts := utils_common.ClipOptions{Profile: "db", SplitBy: "dir", OutDir: "bundles"}
+	require.NoError(t, opts.Validate(), "the flags alone are valid")
+
+	_, err = fakeStringUtils.BuildBundle(context.Background(), &opts)
+	require.Error(t, err, "the profile's token budget cannot be split by dir")
+	require.Contains(t, err.Error(), "validate:")
+	require.Equal(t, 0, osLayer.BuildBundleCallCount())
+}
+
+func Test_WriteArchive_Selects_Like_BuildBundle(t *testing.T) {
+	conf := config.Config{}
+	conf.CopyToClipboard = config.CopyToClipboard{
+		Exclusions: []string{".git"},
+	}
+	osLayer := clifakes.FakeStringUtils{}
+
+	fakeStringUtils, err := New(&conf, &osLayer)
+	require.NoError(t, err, "config error")
+
+	_, err = fakeStringUtils.WriteArchive(context.Background(), &utils_common.ClipOptions{
+		Paths:   []string{"test"},
+		Exclude: []string{" **/fakes/** "},
+		Archive: " out.zip ",
+	})
+	require.NoError(t, err, "no error expected")
+
+	_, opts := osLayer.WriteArchiveArgsForCall(0)
+	require.Equal(t, []string{".git"}, opts.Exclusions)
+	require.Equal(t, []string{"**/fakes/**"}, opts.Exclude)
+	require.Equal(t, "out.zip", opts.Archive)
+}
+
+func Test_WriteBundleToDir_Fail_Missing_Dir(t *testing.T) {
+	conf := config.Config{}
+	osLayer := clifakes.FakeStringUtils{}
+
+	fakeStringUtils, err := New(&conf, &osLayer)
+	require.NoError(t, err, "config error")
+
+	err = fakeStringUtils.WriteBundleToDir(&utils_common.Bundle{}, " ")
+	require.ErrorIs(t, err, models.ErrOutDirMissing)
+	require.ErrorIs(t, fakeStringUtils.WriteBundleToDir(nil, "out"), models.ErrBundleNil)
+}
@@ -2,44 +2,230 @@
 package string_utilsfakes
 
 import (
+	"context"
+	"io"
 	"sync"
+
+	"github.com/dembygenesis/local.tools/internal/utils_common"
 )
 
 type FakeOsLayer struct {
-	CopyRootPathToClipboardStub        func(string, []string) ([]string, error)
-	copyRootPathToClipboardMutex       sync.RWMutex
-	copyRootPathToClipboardArgsForCall []struct {
-		arg1 string
-		arg2 []string
-	}
-	copyRootPathToClipboardReturns struct {
-		result1 []string
+	BuildBundleStub        func(context.Context, *utils_common.ClipOptions) (*utils_common.Bundle, error)
+	buildBundleMutex       sync.RWMutex
+	buildBundleArgsForCall []struct {
+		arg1 context.Context
+		arg2 *utils_common.ClipOptions
+	}
+	buildBundleReturns struct {
+		result1 *utils_common.Bundle
+		result2 error
+	}
+	buildBundleReturnsOnCall map[int]struct {
+		result1 *utils_common.Bundle
+		result2 error
+	}
+	ClipBundleStub        func(*utils_common.Bundle) error
+	clipBundleMutex       sync.RWMutex
+	clipBundleArgsForCall []struct {
+		arg1 *utils_common.Bundle
+	}
+	clipBundleReturns struct {
+		result1 error
+	}
+	clipBundleReturnsOnCall map[int]struct {
+		result1 error
+	}
+	WriteArchiveStub        func(context.Context, *utils_common.ClipOptions) (*utils_common.ArchiveResult, error)
+	writeArchiveMutex       sync.RWMutex
+	writeArchiveArgsForCall []struct {
+		arg1 context.Context
+		arg2 *utils_common.ClipOptions
+	}
+	writeArchiveReturns struct {
+		result1 *utils_common.ArchiveResult
 		result2 error
 	}
-	copyRootPathToClipboardReturnsOnCall map[int]struct {
-		result1 []string
+	writeArchiveReturnsOnCall map[int]struct {
+		result1 *utils_common.ArchiveResult
 		result2 error
 	}
+	WriteBundleStub        func(*utils_common.Bundle, io.Writer) error
+	writeBundleMutex       sync.RWMutex
+	writeBundleArgsForCall []struct {
+		arg1 *utils_common.Bundle
+		arg2 io.Writer
+	}
+	writeBundleReturns struct {
+		result1 error
+	}
+	writeBundleReturnsOnCall map[int]struct {
+		result1 error
+	}
+	WriteBundleToDirStub        func(*utils_common.Bundle, string) error
+	writeBundleToDirMutex       sync.RWMutex
+	writeBundleToDirArgsForCall []struct {
+		arg1 *utils_common.Bundle
+		arg2 string
+	}
+	writeBundleToDirReturns struct {
+		result1 error
+	}
+	writeBundleToDirReturnsOnCall map[int]struct {
+		result1 error
+	}
+	WriteBundleToFileStub        func(*utils_common.Bundle, string) error
+	writeBundleToFileMutex       sync.RWMutex
+	writeBundleToFileArgsForCall []struct {
+		arg1 *utils_common.Bundle
+		arg2 string
+	}
+	writeBundleToFileReturns struct {
+		result1 error
+	}
+	writeBundleToFileReturnsOnCall map[int]struct {
+		result1 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *FakeOsLayer) CopyRootPathToClipboard(arg1 string, arg2 []string) ([]string, error) {
-	var arg2Copy []string
-	if arg2 != nil {
-		arg2Copy = make([]string, len(arg2))
-		copy(arg2Copy, arg2)
-	}
-	fake.copyRootPathToClipboardMutex.Lock()
-	ret, specificReturn := fake.copyRootPathToClipboardReturnsOnCall[len(fake.copyRootPathToClipboardArgsForCall)]
-	fake.copyRootPathToClipboardArgsForCall = append(fake.copyRootPathToClipboardArgsForCall, struct {
-		arg1 string
-		arg2 []string
-	}{arg1, arg2Copy})
-	stub := fake.CopyRootPathToClipboardStub
-	fakeReturns := fake.copyRootPathToClipboardReturns
-	fake.recordInvocation("CopyRootPathToClipboard", []interface{}{arg1, arg2Copy})
-	fake.copyRootPathToClipboardMutex.Unlock()
+func (fake *FakeOsLayer) BuildBundle(arg1 context.Context, arg2 *utils_common.ClipOptions) (*utils_common.Bundle, error) {
+	fake.buildBundleMutex.Lock()
+	ret, specificReturn := fake.buildBundleReturnsOnCall[len(fake.buildBundleArgsForCall)]
+	fake.buildBundleArgsForCall = append(fake.buildBundleArgsForCall, struct {
+		arg1 context.Context
+		arg2 *utils_common.ClipOptions
+	}{arg1, arg2})
+	stub := fake.BuildBundleStub
+	fakeReturns := fake.buildBundleReturns
+	fake.recordInvocation("BuildBundle", []interface{}{arg1, arg2})
+	fake.buildBundleMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeOsLayer) BuildBundleCallCount() int {
+	fake.buildBundleMutex.RLock()
+	defer fake.buildBundleMutex.RUnlock()
+	return len(fake.buildBundleArgsForCall)
+}
+
+func (fake *FakeOsLayer) BuildBundleCalls(stub func(context.Context, *utils_common.ClipOptions) (*utils_common.Bundle, error)) {
+	fake.buildBundleMutex.Lock()
+	defer fake.buildBundleMutex.Unlock()
+	fake.BuildBundleStub = stub
+}
+
+func (fake *FakeOsLayer) BuildBundleArgsForCall(i int) (context.Context, *utils_common.ClipOptions) {
+	fake.buildBundleMutex.RLock()
+	defer fake.buildBundleMutex.RUnlock()
+	argsForCall := fake.buildBundleArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeOsLayer) BuildBundleReturns(result1 *utils_common.Bundle, result2 error) {
+	fake.buildBundleMutex.Lock()
+	defer fake.buildBundleMutex.Unlock()
+	fake.BuildBundleStub = nil
+	fake.buildBundleReturns = struct {
+		result1 *utils_common.Bundle
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeOsLayer) BuildBundleReturnsOnCall(i int, result1 *utils_common.Bundle, result2 error) {
+	fake.buildBundleMutex.Lock()
+	defer fake.buildBundleMutex.Unlock()
+	fake.BuildBundleStub = nil
+	if fake.buildBundleReturnsOnCall == nil {
+		fake.buildBundleReturnsOnCall = make(map[int]struct {
+			result1 *utils_common.Bundle
+			result2 error
+		})
+	}
+	fake.buildBundleReturnsOnCall[i] = struct {
+		result1 *utils_common.Bundle
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeOsLayer) ClipBundle(arg1 *utils_common.Bundle) error {
+	fake.clipBundleMutex.Lock()
+	ret, specificReturn := fake.clipBundleReturnsOnCall[len(fake.clipBundleArgsForCall)]
+	fake.clipBundleArgsForCall = append(fake.clipBundleArgsForCall, struct {
+		arg1 *utils_common.Bundle
+	}{arg1})
+	stub := fake.ClipBundleStub
+	fakeReturns := fake.clipBundleReturns
+	fake.recordInvocation("ClipBundle", []interface{}{arg1})
+	fake.clipBundleMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeOsLayer) ClipBundleCallCount() int {
+	fake.clipBundleMutex.RLock()
+	defer fake.clipBundleMutex.RUnlock()
+	return len(fake.clipBundleArgsForCall)
+}
+
+func (fake *FakeOsLayer) ClipBundleCalls(stub func(*utils_common.Bundle) error) {
+	fake.clipBundleMutex.Lock()
+	defer fake.clipBundleMutex.Unlock()
+	fake.ClipBundleStub = stub
+}
+
+func (fake *FakeOsLayer) ClipBundleArgsForCall(i int) *utils_common.Bundle {
+	fake.clipBundleMutex.RLock()
+	defer fake.clipBundleMutex.RUnlock()
+	argsForCall := fake.clipBundleArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeOsLayer) ClipBundleReturns(result1 error) {
+	fake.clipBundleMutex.Lock()
+	defer fake.clipBundleMutex.Unlock()
+	fake.ClipBundleStub = nil
+	fake.clipBundleReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeOsLayer) ClipBundleReturnsOnCall(i int, result1 error) {
+	fake.clipBundleMutex.Lock()
+	defer fake.clipBundleMutex.Unlock()
+	fake.ClipBundleStub = nil
+	if fake.clipBundleReturnsOnCall == nil {
+		fake.clipBundleReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.clipBundleReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeOsLayer) WriteArchive(arg1 context.Context, arg2 *utils_common.ClipOptions) (*utils_common.ArchiveResult, error) {
+	fake.writeArchiveMutex.Lock()
+	ret, specificReturn := fake.writeArchiveReturnsOnCall[len(fake.writeArchiveArgsForCall)]
+	fake.writeArchiveArgsForCall = append(fake.writeArchiveArgsForCall, struct {
+		arg1 context.Context
+		arg2 *utils_common.ClipOptions
+	}{arg1, arg2})
+	stub := fake.WriteArchiveStub
+	fakeReturns := fake.writeArchiveReturns
+	fake.recordInvocation("WriteArchive", []interface{}{arg1, arg2})
+	fake.writeArchiveMutex.Unlock()
 	if stub != nil {
 		return stub(arg1, arg2)
 	}
@@ -49,56 +235,252 @@ func (fake *FakeOsLayer) CopyRootPathToClipboard(arg1 string, arg2 []string) ([]
 	return fakeReturns.result1, fakeReturns.result2
 }
 
-func (fake *FakeOsLayer) CopyRootPathToClipboardCallCount() int {
-	fake.copyRootPathToClipboardMutex.RLock()
-	defer fake.copyRootPathToClipboardMutex.RUnlock()
-	return len(fake.copyRootPathToClipboardArgsForCall)
+func (fake *FakeOsLayer) WriteArchiveCallCount() int {
+	fake.writeArchiveMutex.RLock()
+	defer fake.writeArchiveMutex.RUnlock()
+	return len(fake.writeArchiveArgsForCall)
 }
 
-func (fake *FakeOsLayer) CopyRootPathToClipboardCalls(stub func(string, []string) ([]string, error)) {
-	fake.copyRootPathToClipboardMutex.Lock()
-	defer fake.copyRootPathToClipboardMutex.Unlock()
-	fake.CopyRootPathToClipboardStub = stub
+func (fake *FakeOsLayer) WriteArchiveCalls(stub func(context.Context, *utils_common.ClipOptions) (*utils_common.ArchiveResult, error)) {
+	fake.writeArchiveMutex.Lock()
+	defer fake.writeArchiveMutex.Unlock()
+	fake.WriteArchiveStub = stub
 }
 
-func (fake *FakeOsLayer) CopyRootPathToClipboardArgsForCall(i int) (string, []string) {
-	fake.copyRootPathToClipboardMutex.RLock()
-	defer fake.copyRootPathToClipboardMutex.RUnlock()
-	argsForCall := fake.copyRootPathToClipboardArgsForCall[i]
+func (fake *FakeOsLayer) WriteArchiveArgsForCall(i int) (context.Context, *utils_common.ClipOptions) {
+	fake.writeArchiveMutex.RLock()
+	defer fake.writeArchiveMutex.RUnlock()
+	argsForCall := fake.writeArchiveArgsForCall[i]
 	return argsForCall.arg1, argsForCall.arg2
 }
 
-func (fake *FakeOsLayer) CopyRootPathToClipboardReturns(result1 []string, result2 error) {
-	fake.copyRootPathToClipboardMutex.Lock()
-	defer fake.copyRootPathToClipboardMutex.Unlock()
-	fake.CopyRootPathToClipboardStub = nil
-	fake.copyRootPathToClipboardReturns = struct {
-		result1 []string
+func (fake *FakeOsLayer) WriteArchiveReturns(result1 *utils_common.ArchiveResult, result2 error) {
+	fake.writeArchiveMutex.Lock()
+	defer fake.writeArchiveMutex.Unlock()
+	fake.WriteArchiveStub = nil
+	fake.writeArchiveReturns = struct {
+		result1 *utils_common.ArchiveResult
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *FakeOsLayer) CopyRootPathToClipboardReturnsOnCall(i int, result1 []string, result2 error) {
-	fake.copyRootPathToClipboardMutex.Lock()
-	defer fake.copyRootPathToClipboardMutex.Unlock()
-	fake.CopyRootPathToClipboardStub = nil
-	if fake.copyRootPathToClipboardReturnsOnCall == nil {
-		fake.copyRootPathToClipboardReturnsOnCall = make(map[int]struct {
-			result1 []string
+func (fake *FakeOsLayer) WriteArchiveReturnsOnCall(i int, result1 *utils_common.ArchiveResult, result2 error) {
+	fake.writeArchiveMutex.Lock()
+	defer fake.writeArchiveMutex.Unlock()
+	fake.WriteArchiveStub = nil
+	if fake.writeArchiveReturnsOnCall == nil {
+		fake.writeArchiveReturnsOnCall = make(map[int]struct {
+			result1 *utils_common.ArchiveResult
 			result2 error
 		})
 	}
-	fake.copyRootPathToClipboardReturnsOnCall[i] = struct {
-		result1 []string
+	fake.writeArchiveReturnsOnCall[i] = struct {
+		result1 *utils_common.ArchiveResult
 		result2 error
 	}{result1, result2}
 }
 
+func (fake *FakeOsLayer) WriteBundle(arg1 *utils_common.Bundle, arg2 io.Writer) error {
+	fake.writeBundleMutex.Lock()
+	ret, specificReturn := fake.writeBundleReturnsOnCall[len(fake.writeBundleArgsForCall)]
+	fake.writeBundleArgsForCall = append(fake.writeBundleArgsForCall, struct {
+		arg1 *utils_common.Bundle
+		arg2 io.Writer
+	}{arg1, arg2})
+	stub := fake.WriteBundleStub
+	fakeReturns := fake.writeBundleReturns
+	fake.recordInvocation("WriteBundle", []interface{}{arg1, arg2})
+	fake.writeBundleMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeOsLayer) WriteBundleCallCount() int {
+	fake.writeBundleMutex.RLock()
+	defer fake.writeBundleMutex.RUnlock()
+	return len(fake.writeBundleArgsForCall)
+}
+
+func (fake *FakeOsLayer) WriteBundleCalls(stub func(*utils_common.Bundle, io.Writer) error) {
+	fake.writeBundleMutex.Lock()
+	defer fake.writeBundleMutex.Unlock()
+	fake.WriteBundleStub = stub
+}
+
+func (fake *FakeOsLayer) WriteBundleArgsForCall(i int) (*utils_common.Bundle, io.Writer) {
+	fake.writeBundleMutex.RLock()
+	defer fake.writeBundleMutex.RUnlock()
+	argsForCall := fake.writeBundleArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeOsLayer) WriteBundleReturns(result1 error) {
+	fake.writeBundleMutex.Lock()
+	defer fake.writeBundleMutex.Unlock()
+	fake.WriteBundleStub = nil
+	fake.writeBundleReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeOsLayer) WriteBundleReturnsOnCall(i int, result1 error) {
+	fake.writeBundleMutex.Lock()
+	defer fake.writeBundleMutex.Unlock()
+	fake.WriteBundleStub = nil
+	if fake.writeBundleReturnsOnCall == nil {
+		fake.writeBundleReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.writeBundleReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeOsLayer) WriteBundleToDir(arg1 *utils_common.Bundle, arg2 string) error {
+	fake.writeBundleToDirMutex.Lock()
+	ret, specificReturn := fake.writeBundleToDirReturnsOnCall[len(fake.writeBundleToDirArgsForCall)]
+	fake.writeBundleToDirArgsForCall = append(fake.writeBundleToDirArgsForCall, struct {
+		arg1 *utils_common.Bundle
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.WriteBundleToDirStub
+	fakeReturns := fake.writeBundleToDirReturns
+	fake.recordInvocation("WriteBundleToDir", []interface{}{arg1, arg2})
+	fake.writeBundleToDirMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeOsLayer) WriteBundleToDirCallCount() int {
+	fake.writeBundleToDirMutex.RLock()
+	defer fake.writeBundleToDirMutex.RUnlock()
+	return len(fake.writeBundleToDirArgsForCall)
+}
+
+func (fake *FakeOsLayer) WriteBundleToDirCalls(stub func(*utils_common.Bundle, string) error) {
+	fake.writeBundleToDirMutex.Lock()
+	defer fake.writeBundleToDirMutex.Unlock()
+	fake.WriteBundleToDirStub = stub
+}
+
+func (fake *FakeOsLayer) WriteBundleToDirArgsForCall(i int) (*utils_common.Bundle, string) {
+	fake.writeBundleToDirMutex.RLock()
+	defer fake.writeBundleToDirMutex.RUnlock()
+	argsForCall := fake.writeBundleToDirArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeOsLayer) WriteBundleToDirReturns(result1 error) {
+	fake.writeBundleToDirMutex.Lock()
+	defer fake.writeBundleToDirMutex.Unlock()
+	fake.WriteBundleToDirStub = nil
+	fake.writeBundleToDirReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeOsLayer) WriteBundleToDirReturnsOnCall(i int, result1 error) {
+	fake.writeBundleToDirMutex.Lock()
+	defer fake.writeBundleToDirMutex.Unlock()
+	fake.WriteBundleToDirStub = nil
+	if fake.writeBundleToDirReturnsOnCall == nil {
+		fake.writeBundleToDirReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.writeBundleToDirReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeOsLayer) WriteBundleToFile(arg1 *utils_common.Bundle, arg2 string) error {
+	fake.writeBundleToFileMutex.Lock()
+	ret, specificReturn := fake.writeBundleToFileReturnsOnCall[len(fake.writeBundleToFileArgsForCall)]
+	fake.writeBundleToFileArgsForCall = append(fake.writeBundleToFileArgsForCall, struct {
+		arg1 *utils_common.Bundle
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.WriteBundleToFileStub
+	fakeReturns := fake.writeBundleToFileReturns
+	fake.recordInvocation("WriteBundleToFile", []interface{}{arg1, arg2})
+	fake.writeBundleToFileMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeOsLayer) WriteBundleToFileCallCount() int {
+	fake.writeBundleToFileMutex.RLock()
+	defer fake.writeBundleToFileMutex.RUnlock()
+	return len(fake.writeBundleToFileArgsForCall)
+}
+
+func (fake *FakeOsLayer) WriteBundleToFileCalls(stub func(*utils_common.Bundle, string) error) {
+	fake.writeBundleToFileMutex.Lock()
+	defer fake.writeBundleToFileMutex.Unlock()
+	fake.WriteBundleToFileStub = stub
+}
+
+func (fake *FakeOsLayer) WriteBundleToFileArgsForCall(i int) (*utils_common.Bundle, string) {
+	fake.writeBundleToFileMutex.RLock()
+	defer fake.writeBundleToFileMutex.RUnlock()
+	argsForCall := fake.writeBundleToFileArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeOsLayer) WriteBundleToFileReturns(result1 error) {
+	fake.writeBundleToFileMutex.Lock()
+	defer fake.writeBundleToFileMutex.Unlock()
+	fake.WriteBundleToFileStub = nil
+	fake.writeBundleToFileReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeOsLayer) WriteBundleToFileReturnsOnCall(i int, result1 error) {
+	fake.writeBundleToFileMutex.Lock()
+	defer fake.writeBundleToFileMutex.Unlock()
+	fake.WriteBundleToFileStub = nil
+	if fake.writeBundleToFileReturnsOnCall == nil {
+		fake.writeBundleToFileReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.writeBundleToFileReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeOsLayer) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
-	fake.copyRootPathToClipboardMutex.RLock()
-	defer fake.copyRootPathToClipboardMutex.RUnlock()
+	fake.buildBundleMutex.RLock()
+	defer fake.buildBundleMutex.RUnlock()
+	fake.clipBundleMutex.RLock()
+	defer fake.clipBundleMutex.RUnlock()
+	fake.writeArchiveMutex.RLock()
+	defer fake.writeArchiveMutex.RUnlock()
+	fake.writeBundleMutex.RLock()
+	defer fake.writeBundleMutex.RUnlock()
+	fake.writeBundleToDirMutex.RLock()
+	defer fake.writeBundleToDirMutex.RUnlock()
+	fake.writeBundleToFileMutex.RLock()
+	defer fake.writeBundleToFileMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
@@ -0,0 +1,214 @@
+package utils_common
+
+import (
+	"fmt"
+	"github.com/atotto/clipboard"
+	"github.com/pmezard/go-difflib/difflib"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	ChangeNew       = "new"
+	ChangeModified  = "modified"
+	ChangeUnchanged = "unchanged"
+)
+
+// ApplyOptions are the settings used to write a bundle back to disk, under
+// Root. The bundle is read from the clipboard or from stdin when Stdin is set.
+type ApplyOptions struct {
+	Root  string `mapstructure:"root" validate:"required" json:"root"`
+	Stdin bool   `mapstructure:"stdin" json:"stdin"`
+}
+
+func (a *ApplyOptions) Validate() error {
+	return ValidateStruct(a)
+}
+
+// FileChange is a bundled file compared against the working tree, with
+// the unified diff of its current contents to the bundled ones.
+type FileChange struct {
+	Path    string      `json:"path"`
+	Status  string      `json:"status"`
+	Diff    string      `json:"diff"`
+	Content string      `json:"-"`
+	Mode    os.FileMode `json:"-"`
+	abs     string
+}
+
+// ApplyPlan is the set of changes a bundle makes under a root,
+// computed without writing anything.
+type ApplyPlan struct {
+	Root    string       `json:"root"`
+	Changes []FileChange `json:"changes"`
+}
+
+// Pending returns the changes that would write a file.
+func (p *ApplyPlan) Pending() []FileChange {
+	pending := make([]FileChange, 0, len(p.Changes))
+	for _, change := range p.Changes {
+		if change.Status != ChangeUnchanged {
+			pending = append(pending, change)
+		}
+	}
+	return pending
+}
+
+// clipboardRead takes the text from the system clipboard.
+var clipboardRead = clipboard.ReadAll
+
+// ReadClipboard returns the text on the clipboard.
+func ReadClipboard() (string, error) {
+	return clipboardRead()
+}
+
+// PlanBundle compares the bundled files against the ones under root. The
+// whole bundle is refused when one of its paths escapes the root, be it
+// through "..", an absolute path or a symbolic link pointing outside of it.
+func PlanBundle(root string, files []BundleFile) (*ApplyPlan, error) {
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return nil, fmt.Errorf("root: %v", err)
+	}
+	realRoot, err := filepath.EvalSymlinks(absRoot)
+	if err != nil {
+		return nil, fmt.Errorf("root: %v", err)
+	}
+
+	plan := ApplyPlan{
+		Root:    absRoot,
+		Changes: make([]FileChange, 0, len(files)),
+	}
+
+	for _, file := range files {
+		abs, err := resolveWithin(absRoot, realRoot, file.Path)
+		if err != nil {
+			return nil, err
+		}
+
+		change := FileChange{
+			Path:    file.Path,
+			Content: file.Content,
+			Mode:    0644,
+			abs:     abs,
+		}
+
+		current := ""
+		info, err := os.Stat(abs)
+		switch {
+		case os.IsNotExist(err):
+			change.Status = ChangeNew
+		case err != nil:
+			return nil, fmt.Errorf("stat %s: %v", file.Path, err)
+		case info.IsDir():
+			return nil, fmt.Errorf("'%s' is a directory", file.Path)
+		default:
+			data, err := os.ReadFile(abs)
+			if err != nil {
+				return nil, fmt.Errorf("read %s: %v", file.Path, err)
+			}
+			current = string(data)
+			change.Mode = info.Mode().Perm()
+			change.Status = ChangeModified
+			if current == file.Content {
+				change.Status = ChangeUnchanged
+			}
+		}
+
+		if change.Status != ChangeUnchanged {
+			change.Diff, err = unifiedDiff(file.Path, current, file.Content, change.Status == ChangeNew)
+			if err != nil {
+				return nil, fmt.Errorf("diff %s: %v", file.Path, err)
+			}
+		}
+
+		plan.Changes = append(plan.Changes, change)
+	}
+
+	return &plan, nil
+}
+
+// ApplyBundle writes the pending changes of the plan, creating the
+// missing directories and keeping the permissions of existing files.
+func ApplyBundle(plan *ApplyPlan) error {
+	for _, change := range plan.Pending() {
+		if err := os.MkdirAll(filepath.Dir(change.abs), 0755); err != nil {
+			return fmt.Errorf("mkdir %s: %v", change.Path, err)
+		}
+		if err := os.WriteFile(change.abs, []byte(change.Content), change.Mode); err != nil {
+			return fmt.Errorf("write %s: %v", change.Path, err)
+		}
+	}
+	return nil
+}
+
+// resolveWithin returns the absolute path of the slash separated name under
+// root or an error when it would land outside of the root (realRoot being
+// the root with its symbolic links resolved).
+func resolveWithin(root string, realRoot string, name string) (string, error) {
+	native := filepath.FromSlash(name)
+	if filepath.IsAbs(native) || filepath.VolumeName(native) != "" || strings.HasPrefix(name, "/") {
+		return "", fmt.Errorf("refusing absolute path '%s'", name)
+	}
+
+	abs := filepath.Join(root, native)
+	if !isWithin(root, abs) {
+		return "", fmt.Errorf("refusing path '%s', it escapes the root", name)
+	}
+
+	// The deepest existing part of the path is resolved,
+	// as it may be a link pointing outside of the root.
+	existing := abs
+	for {
+		if _, err := os.Lstat(existing); err == nil {
+			break
+		}
+		existing = filepath.Dir(existing)
+	}
+	resolved, err := filepath.EvalSymlinks(existing)
+	if err != nil {
+		return "", fmt.Errorf("resolve %s: %v", name, err)
+	}
+	if !isWithin(realRoot, resolved) {
+		return "", fmt.Errorf("refusing path '%s', it links outside of the root", name)
+	}
+
+	return abs, nil
+}
+
+// isWithin tells whether path is root or below it.
+func isWithin(root string, path string) bool {
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return false
+	}
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) && !filepath.IsAbs(rel)
+}
+
+// unifiedDiff returns the "a/path" to "b/path" unified diff of the contents,
+// from /dev/null for new files.
+func unifiedDiff(name string, from string, to string, created bool) (string, error) {
+	fromFile := "a/" + name
+	if created {
+		fromFile = "/dev/null"
+	}
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        diffLines(from),
+		B:        diffLines(to),
+		FromFile: fromFile,
+		ToFile:   "b/" + name,
+		Context:  3,
+	})
+}
+
+// diffLines splits the contents into lines for a diff, each ending with a
+// line break (difflib.SplitLines adds an empty line after the last break).
+func diffLines(content string) []string {
+	lines := strings.SplitAfter(content, "\n")
+	if lines[len(lines)-1] == "" {
+		return lines[:len(lines)-1]
+	}
+	lines[len(lines)-1] += "\n"
+	return lines
+}
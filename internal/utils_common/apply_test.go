@@ -0,0 +1,83 @@
+package utils_common
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPlanBundle_Statuses_And_Diffs(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(root, "same.txt"), []byte("same\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "run.sh"), []byte("echo 1\n"), 0755))
+
+	plan, err := PlanBundle(root, []BundleFile{
+		{Path: "same.txt", Content: "same\n"},
+		{Path: "run.sh", Content: "echo 2\n"},
+		{Path: "sub/new.txt", Content: "new\n"},
+	})
+	require.NoError(t, err)
+
+	require.Len(t, plan.Changes, 3)
+	assert.Equal(t, ChangeUnchanged, plan.Changes[0].Status)
+	assert.Empty(t, plan.Changes[0].Diff)
+
+	assert.Equal(t, ChangeModified, plan.Changes[1].Status)
+	assert.Equal(t, "--- a/run.sh\n+++ b/run.sh\n@@ -1 +1 @@\n-echo 1\n+echo 2\n", plan.Changes[1].Diff)
+
+	assert.Equal(t, ChangeNew, plan.Changes[2].Status)
+	assert.Equal(t, "--- /dev/null\n+++ b/sub/new.txt\n@@ -0,0 +1 @@\n+new\n", plan.Changes[2].Diff)
+
+	assert.Len(t, plan.Pending(), 2)
+}
+
+func TestPlanBundle_Refuses_Escaping_Paths(t *testing.T) {
+	outside := t.TempDir()
+	root := t.TempDir()
+	require.NoError(t, os.Symlink(outside, filepath.Join(root, "out")))
+
+	testCases := map[string]string{
+		"Parent":   "../x.txt",
+		"Nested":   "a/../../x.txt",
+		"Absolute": "/etc/x.txt",
+		"Link":     "out/x.txt",
+	}
+
+	for name, path := range testCases {
+		t.Run(name, func(t *testing.T) {
+			_, err := PlanBundle(root, []BundleFile{
+				{Path: "ok.txt", Content: "ok\n"},
+				{Path: path, Content: "x\n"},
+			})
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "refusing")
+		})
+	}
+}
+
+func TestApplyBundle(t *testing.T) {
+	root := t.TempDir()
+	script := filepath.Join(root, "run.sh")
+	require.NoError(t, os.WriteFile(script, []byte("echo 1\n"), 0755))
+
+	plan, err := PlanBundle(root, []BundleFile{
+		{Path: "run.sh", Content: "echo 2\n"},
+		{Path: "a/b/new.txt", Content: "new\n"},
+	})
+	require.NoError(t, err)
+	require.NoError(t, ApplyBundle(plan))
+
+	data, err := os.ReadFile(script)
+	require.NoError(t, err)
+	assert.Equal(t, "echo 2\n", string(data))
+
+	info, err := os.Stat(script)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0755), info.Mode().Perm(), "permissions are kept")
+
+	data, err = os.ReadFile(filepath.Join(root, "a", "b", "new.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "new\n", string(data))
+}
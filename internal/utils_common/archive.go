@@ -0,0 +1,237 @@
+package utils_common
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// ArchiveZip and ArchiveTarGz are the archive formats, picked by extension.
+	ArchiveZip   = "zip"
+	ArchiveTarGz = "tar.gz"
+
+	// archiveManifest is the name of the manifest added at the archive's root.
+	archiveManifest = "CLIP_MANIFEST.txt"
+)
+
+// ArchiveEntry is a file packaged in an archive.
+type ArchiveEntry struct {
+	Path string `json:"path"`
+	Size int64  `json:"size"`
+	Link string `json:"link,omitempty"`
+}
+
+// ArchiveResult summarizes an archive: its Files are relative to the Base
+// directory and so are the Redacted secrets. Bytes is the archive's size.
+type ArchiveResult struct {
+	Path     string         `json:"path"`
+	Base     string         `json:"base"`
+	Files    []ArchiveEntry `json:"files"`
+	Redacted []Redaction    `json:"redacted"`
+	Bytes    int64          `json:"bytes"`
+}
+
+// archiveFormat returns the format of the archive, by the path's extension.
+func archiveFormat(path string) (string, error) {
+	lower := strings.ToLower(path)
+	switch {
+	case strings.HasSuffix(lower, ".zip"):
+		return ArchiveZip, nil
+	case strings.HasSuffix(lower, ".tar.gz"), strings.HasSuffix(lower, ".tgz"):
+		return ArchiveTarGz, nil
+	}
+	return "", fmt.Errorf("unsupported archive '%s', expected a .zip, .tar.gz or .tgz file", path)
+}
+
+// WriteArchive packages the files selected by the options, with their paths
+// relative to the common base directory, into the Archive file (a zip or a
+// gzipped tarball), along with a manifest listing them with their sizes.
+//
+// The files are selected exactly like a bundle's, so both hold the same
+// files. Their contents are written as they are on disk, except for the
+// secrets of text files, masked unless NoRedact is set. Preserved symbolic
+// links are stored as links in tarballs and as placeholders in zips.
+func WriteArchive(ctx context.Context, opts *ClipOptions) (*ArchiveResult, error) {
+	format, err := archiveFormat(opts.Archive)
+	if err != nil {
+		return nil, err
+	}
+
+	archivePath, err := filepath.Abs(opts.Archive)
+	if err != nil {
+		return nil, err
+	}
+
+	base, files, _, err := selectFiles(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	result := ArchiveResult{
+		Path:     opts.Archive,
+		Base:     displayPath(base),
+		Files:    make([]ArchiveEntry, 0, len(files)),
+		Redacted: make([]Redaction, 0),
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(archivePath), ".clip-archive-*")
+	if err != nil {
+		return nil, fmt.Errorf("create archive: %v", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	writer := newArchiveWriter(format, tmp)
+	for _, file := range files {
+		if err := ctx.Err(); err != nil {
+			tmp.Close()
+			return nil, err
+		}
+		if file.Path == archivePath {
+			continue // A previous archive, found below the paths
+		}
+
+		info, err := os.Stat(file.Path)
+		if err != nil && file.Link == "" {
+			tmp.Close()
+			return nil, err
+		}
+
+		entry := ArchiveEntry{Path: file.Rel, Link: file.Link}
+		var content []byte
+		if file.Link == "" {
+			if content, err = os.ReadFile(file.Path); err != nil {
+				tmp.Close()
+				return nil, err
+			}
+			if _, binary := detectBinary(content); !binary && !opts.NoRedact {
+				masked, redactions := redactSecrets(file.Rel, string(content))
+				content = []byte(masked)
+				result.Redacted = append(result.Redacted, redactions...)
+			}
+		}
+		entry.Size = int64(len(content))
+
+		modified := time.Now()
+		if info != nil {
+			modified = info.ModTime()
+		}
+		if err := writer.add(entry, content, modified); err != nil {
+			tmp.Close()
+			return nil, fmt.Errorf("add '%s': %v", file.Rel, err)
+		}
+		result.Files = append(result.Files, entry)
+	}
+
+	manifest := []byte(formatArchiveManifest(result.Base, result.Files))
+	if err := writer.add(ArchiveEntry{Path: archiveManifest, Size: int64(len(manifest))}, manifest, time.Now()); err != nil {
+		tmp.Close()
+		return nil, fmt.Errorf("add manifest: %v", err)
+	}
+
+	if err := writer.Close(); err != nil {
+		tmp.Close()
+		return nil, fmt.Errorf("close archive: %v", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return nil, fmt.Errorf("close archive: %v", err)
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return nil, err
+	}
+	if err := os.Rename(tmp.Name(), archivePath); err != nil {
+		return nil, fmt.Errorf("write archive: %v", err)
+	}
+
+	if info, err := os.Stat(archivePath); err == nil {
+		result.Bytes = info.Size()
+	}
+
+	return &result, nil
+}
+
+// formatArchiveManifest lists the files of an archive with their sizes in
+// bytes and the totals.
+func formatArchiveManifest(base string, files []ArchiveEntry) string {
+	width := len("Path")
+	var total int64
+	for _, file := range files {
+		width = max(width, len(file.Path))
+		total += file.Size
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Files clipped from %s\n\n", base)
+	fmt.Fprintf(&sb, "%-*s  %s\n", width, "Path", "Bytes")
+	for _, file := range files {
+		size := strconv.FormatInt(file.Size, 10)
+		if file.Link != "" {
+			size = "-> " + file.Link
+		}
+		fmt.Fprintf(&sb, "%-*s  %s\n", width, file.Path, size)
+	}
+	fmt.Fprintf(&sb, "\n%d files, %d bytes (%s)\n", len(files), total, FormatBytes(total))
+	return sb.String()
+}
+
+// archiveWriter adds entries to an archive of either format.
+type archiveWriter struct {
+	zip  *zip.Writer
+	gzip *gzip.Writer
+	tar  *tar.Writer
+}
+
+func newArchiveWriter(format string, w io.Writer) *archiveWriter {
+	if format == ArchiveZip {
+		return &archiveWriter{zip: zip.NewWriter(w)}
+	}
+	gz := gzip.NewWriter(w)
+	return &archiveWriter{gzip: gz, tar: tar.NewWriter(gz)}
+}
+
+// add writes the entry. A symbolic link is stored as a link in a tarball
+// and as a placeholder naming its target in a zip.
+func (a *archiveWriter) add(entry ArchiveEntry, content []byte, modified time.Time) error {
+	if a.zip != nil {
+		if entry.Link != "" {
+			content = []byte(symlinkPlaceholder(entry.Link))
+		}
+		header := &zip.FileHeader{Name: entry.Path, Method: zip.Deflate, Modified: modified}
+		header.SetMode(0644)
+		w, err := a.zip.CreateHeader(header)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(content)
+		return err
+	}
+
+	header := &tar.Header{Name: entry.Path, Mode: 0644, Size: int64(len(content)), ModTime: modified, Typeflag: tar.TypeReg}
+	if entry.Link != "" {
+		header = &tar.Header{Name: entry.Path, Mode: 0777, Linkname: entry.Link, ModTime: modified, Typeflag: tar.TypeSymlink}
+		content = nil
+	}
+	if err := a.tar.WriteHeader(header); err != nil {
+		return err
+	}
+	_, err := a.tar.Write(content)
+	return err
+}
+
+func (a *archiveWriter) Close() error {
+	if a.zip != nil {
+		return a.zip.Close()
+	}
+	if err := a.tar.Close(); err != nil {
+		return err
+	}
+	return a.gzip.Close()
+}
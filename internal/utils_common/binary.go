@@ -0,0 +1,74 @@
+package utils_common
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+)
+
+// sniffLen is the number of leading bytes inspected to
+// tell text from binary content, the same amount git uses.
+const sniffLen = 8000
+
+// detectBinary returns the content's MIME type and whether
+// it should be treated as binary (non-text) content.
+func detectBinary(data []byte) (string, bool) {
+	sample := data
+	if len(sample) > sniffLen {
+		sample = sample[:sniffLen]
+	}
+
+	mimeType := strings.TrimSpace(strings.SplitN(http.DetectContentType(sample), ";", 2)[0])
+
+	if bytes.IndexByte(sample, 0) >= 0 {
+		return mimeType, true
+	}
+
+	if !strings.HasPrefix(mimeType, "text/") {
+		return mimeType, true
+	}
+
+	if len(sample) < len(data) {
+		sample = trimIncompleteRune(sample)
+	}
+
+	return mimeType, !utf8.Valid(sample)
+}
+
+// trimIncompleteRune drops a trailing partial UTF-8 sequence,
+// which is expected when content is cut at an arbitrary length.
+func trimIncompleteRune(b []byte) []byte {
+	for i := 1; i < utf8.UTFMax && i <= len(b); i++ {
+		if utf8.RuneStart(b[len(b)-i]) {
+			if !utf8.FullRune(b[len(b)-i:]) {
+				return b[:len(b)-i]
+			}
+			break
+		}
+	}
+	return b
+}
+
+// binaryPlaceholder is the one-line summary that stands in for binary content.
+func binaryPlaceholder(size int, mimeType string) string {
+	return fmt.Sprintf("[binary, %s, %s]", FormatBytes(int64(size)), mimeType)
+}
+
+// FormatBytes renders a byte count in a human-readable form (e.g "34 KB").
+func FormatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+
+	value := strings.TrimSuffix(fmt.Sprintf("%.1f", float64(n)/float64(div)), ".0")
+	return fmt.Sprintf("%s %cB", value, "KMGTPE"[exp])
+}
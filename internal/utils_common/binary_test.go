@@ -0,0 +1,54 @@
+package utils_common
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func Test_detectBinary(t *testing.T) {
+	type testCase struct {
+		name     string
+		data     []byte
+		mimeType string
+		binary   bool
+	}
+
+	testCases := []testCase{
+		{name: "Empty", data: []byte{}, mimeType: "text/plain", binary: false},
+		{name: "Go Source", data: []byte("package main\n\nfunc main() {}\n"), mimeType: "text/plain", binary: false},
+		{name: "UTF-8 Text", data: []byte("héllo wörld ✅"), mimeType: "text/plain", binary: false},
+		{name: "JPEG", data: []byte("\xFF\xD8\xFF\xE0\x00\x10JFIF\x00"), mimeType: "image/jpeg", binary: true},
+		{name: "SQLite", data: []byte("SQLite format 3\x00\x10\x00"), mimeType: "application/octet-stream", binary: true},
+		{name: "NUL Byte", data: []byte("abc\x00def"), mimeType: "application/octet-stream", binary: true},
+		{name: "Invalid UTF-8", data: []byte("caf\xe9 cr\xe8me"), mimeType: "text/plain", binary: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mimeType, binary := detectBinary(tc.data)
+			assert.Equal(t, tc.mimeType, mimeType)
+			assert.Equal(t, tc.binary, binary)
+		})
+	}
+}
+
+func Test_detectBinary_Cut_Rune_At_Sniff_Length(t *testing.T) {
+	data := make([]byte, 0, sniffLen+4)
+	for len(data) < sniffLen-1 {
+		data = append(data, 'a')
+	}
+	data = append(data, "é"...) // The 2-byte rune straddles the sniff length
+
+	_, binary := detectBinary(data)
+	assert.False(t, binary)
+}
+
+func Test_binaryPlaceholder(t *testing.T) {
+	assert.Equal(t, "[binary, 34 KB, image/jpeg]", binaryPlaceholder(34*1024, "image/jpeg"))
+}
+
+func TestFormatBytes(t *testing.T) {
+	assert.Equal(t, "512 B", FormatBytes(512))
+	assert.Equal(t, "1.5 KB", FormatBytes(1536))
+	assert.Equal(t, "2 MB", FormatBytes(2*1024*1024))
+}
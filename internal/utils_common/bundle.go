@@ -0,0 +1,213 @@
+package utils_common
+
+import (
+	"context"
+	"fmt"
+	"github.com/dembygenesis/local.tools/internal/common"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Bundle is the formatted contents of the selected files, split into
+// parts when a token budget is set. It is built independently of where
+// it is written to. A bundle split by directory, package or size also
+// names the file of every part and holds their Index.
+type Bundle struct {
+	Parts  []string    `json:"parts"`
+	Names  []string    `json:"names,omitempty"`
+	Index  string      `json:"index,omitempty"`
+	Result *ClipResult `json:"result"`
+}
+
+// String joins the parts of the bundle.
+func (b *Bundle) String() string {
+	return strings.Join(b.Parts, "")
+}
+
+// visit collects the files selected by the filter, for a walk starting
+// at start. The start itself is never pruned, as it was asked for explicitly.
+// The walk is aborted once the context is done.
+func visit(ctx context.Context, files *[]string, filter *fileFilter, start string) filepath.WalkFunc {
+	excludedDirs := make(map[string]bool)
+
+	return func(path string, info os.FileInfo, err error) error {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+
+		if err != nil {
+			fmt.Printf("Encountered an error accessing path %s: %s\n", path, err)
+			return err
+		}
+
+		parentExcluded := excludedDirs[filepath.Dir(path)]
+
+		if info.IsDir() {
+			if path == start {
+				return nil
+			}
+			prune, excluded, err := filter.pruneDir(path, parentExcluded)
+			if err != nil {
+				return err
+			}
+			if prune {
+				return filepath.SkipDir // Skip the entire directory
+			}
+			excludedDirs[path] = excluded
+			return nil
+		}
+
+		selected, err := filter.selectsFile(path, parentExcluded)
+		if err != nil {
+			fmt.Printf("Error calculating relative path for %s: %s\n", path, err)
+			return err
+		}
+		if selected {
+			*files = append(*files, path)
+		}
+
+		return nil
+	}
+}
+
+// BuildBundle reads the selected files of every path (or the files picked
+// by git), masks their secrets, truncates them to the size limits and lays out
+// their contents by the selected format. Files are named relative to the common
+// base directory of the paths. With a Symbol, snippets of the Go files are
+// bundled instead of the files themselves. With SplitBy, the files are
+// split into one bundle per directory, Go package or size budget, each
+// opened by a header and its tree.
+//
+// Files are read concurrently. The walk and the reads stop as soon as the
+// context is done.
+func BuildBundle(ctx context.Context, opts *ClipOptions) (*Bundle, error) {
+	logger := common.GetLogger(ctx)
+
+	base, files, excluded, err := selectFiles(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	result := ClipResult{
+		Base:     displayPath(base),
+		Files:    make([]string, 0, len(files)),
+		Skipped:  make([]SkippedFile, 0),
+		Excluded: excluded,
+		Redacted: make([]Redaction, 0),
+		Minified: make([]MinifiedFile, 0),
+	}
+
+	formatter, err := NewFormatter(opts.Format)
+	if err != nil {
+		return nil, fmt.Errorf("formatter: %v", err)
+	}
+
+	var loaded []loadedFile
+	if opts.Symbol != "" {
+		files, loaded, err = loadSymbol(ctx, files, opts)
+		if err != nil {
+			return nil, fmt.Errorf("symbol: %w", err)
+		}
+	} else {
+		loaded, err = loadFiles(ctx, files, opts)
+		if err != nil {
+			return nil, fmt.Errorf("read files: %v", err)
+		}
+	}
+
+	bundleFiles := make([]BundleFile, 0, len(files))
+	bundleSources := make([]selectedFile, 0, len(files))
+	binaryFiles := make([]bool, 0, len(files))
+	for i, selected := range files {
+		file := selected.Rel
+		if loaded[i].Binary {
+			result.Skipped = append(result.Skipped, SkippedFile{
+				Path:   file,
+				Reason: loaded[i].Content,
+			})
+			if opts.Binary == BinarySkip {
+				continue
+			}
+		} else {
+			result.Files = append(result.Files, file)
+			result.Redacted = append(result.Redacted, loaded[i].Redactions...)
+			if loaded[i].Saved > 0 {
+				result.Minified = append(result.Minified, MinifiedFile{
+					Path:  file,
+					Saved: loaded[i].Saved,
+				})
+			}
+			if opts.LineNumbers {
+				loaded[i].Content = numberLinesFrom(loaded[i].Content, max(loaded[i].First, 1))
+			}
+		}
+
+		bundleFiles = append(bundleFiles, BundleFile{
+			Path:    file,
+			Content: loaded[i].Content,
+			Size:    loaded[i].Size,
+		})
+		bundleSources = append(bundleSources, selected)
+		binaryFiles = append(binaryFiles, loaded[i].Binary)
+	}
+
+	result.Truncated = applySizeLimits(bundleFiles, binaryFiles, opts.MaxFileBytes, opts.MaxTotalBytes)
+	result.Largest = largestFiles(bundleFiles, largestContributors)
+
+	result.Stats = fileStats(bundleFiles, formatter)
+
+	if opts.MaxTokens > 0 {
+		for _, stat := range result.Stats {
+			if stat.Tokens > opts.MaxTokens {
+				logger.Warnf("file '%s' alone exceeds the %d token budget", stat.Path, opts.MaxTokens)
+			}
+		}
+	}
+
+	var parts, names []string
+	var index string
+	switch {
+	case opts.SplitBy != "":
+		splits, err := splitBundleFiles(result.Base, bundleSources, bundleFiles, formatter, opts.SplitBy, opts.Format)
+		if err != nil {
+			return nil, fmt.Errorf("split: %v", err)
+		}
+		for i, split := range splits {
+			marker := splitMarker(i+1, len(splits), split.Group)
+			parts = append(parts, formatter.FormatBundle(marker, bundleTree(result.Base, split.Files), split.Files))
+			names = append(names, split.Name)
+		}
+		index = formatSplitIndex(result.Base, opts.SplitBy, splits, parts)
+	case opts.TreeOnly:
+		parts = []string{formatter.FormatBundle("", bundleTree(result.Base, bundleFiles), nil)}
+	case opts.Tree:
+		parts = chunkFiles(bundleFiles, formatter, opts.MaxTokens, bundleTree(result.Base, bundleFiles))
+	default:
+		parts = chunkFiles(bundleFiles, formatter, opts.MaxTokens, "")
+	}
+
+	for _, part := range parts {
+		result.Tokens += EstimateTokens(part)
+	}
+	result.Chunks = len(parts)
+
+	return &Bundle{
+		Parts:  parts,
+		Names:  names,
+		Index:  index,
+		Result: &result,
+	}, nil
+}
+
+// bundleTree renders the tree of the bundled files, under the base.
+func bundleTree(base string, files []BundleFile) string {
+	entries := make([]TreeEntry, 0, len(files))
+	for _, file := range files {
+		entries = append(entries, TreeEntry{
+			Path: file.Path,
+			Size: file.Size,
+		})
+	}
+	return RenderTree(base, entries)
+}
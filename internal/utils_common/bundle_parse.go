@@ -0,0 +1,256 @@
+package utils_common
+
+import (
+	"encoding/json"
+	"errors"
+	"html"
+	"io"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// ErrNoBundleFiles is returned for text in which no file could be found.
+var ErrNoBundleFiles = errors.New("no files found in the bundle")
+
+var (
+	// plainHeader is the "--- path ---" header of the plain format.
+	plainHeader = regexp.MustCompile(`^--- (\S.*?) ---\s*$`)
+	// xmlHeader opens a file of the xml format and its CDATA section.
+	xmlHeader = regexp.MustCompile(`^<file path="([^"]+)">(<!\[CDATA\[)?\s*$`)
+	// fenceOpen opens a fenced code block, e.g "```go".
+	fenceOpen = regexp.MustCompile("^ {0,3}(`{3,}|~{3,})\\s*([^`]*)$")
+	// pathHeading names the file of the code block that follows it, e.g
+	// "### path", "**path**", "`path`" or "File: path".
+	pathHeading = regexp.MustCompile("^(?:#{1,6}\\s+|(?i:file|path):\\s*)?(?:\\*\\*)?`?([^`*\\s]+?)`?(?:\\*\\*)?:?\\s*$")
+	// infoPath picks a path out of a code block's info string, e.g
+	// "go:internal/x.go" or `go title="internal/x.go"`.
+	infoPath = regexp.MustCompile(`^(?:[A-Za-z0-9_+-]+:|(?:title|path|file)=)?"?([^"\s]+?)"?$`)
+)
+
+// ParseBundle extracts the files of a bundle, as produced by clip-file-contents
+// in any format or as it comes back from a chat. Besides the bundle formats,
+// fenced code blocks are taken as files when their path is given by the line
+// above them (e.g "### path" or "**path**") or in their info string (e.g
+// "```go:path"). Prose in between is ignored and a path given twice keeps
+// its last contents.
+func ParseBundle(text string) ([]BundleFile, error) {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+
+	files, ok := parseJSONBundle(text)
+	if !ok {
+		files = parseTextBundle(text)
+	}
+
+	byPath := make(map[string]int)
+	unique := make([]BundleFile, 0, len(files))
+	for _, file := range files {
+		name, err := cleanBundlePath(file.Path)
+		if err != nil {
+			return nil, err
+		}
+		file.Path = name
+		if i, found := byPath[name]; found {
+			unique[i] = file
+			continue
+		}
+		byPath[name] = len(unique)
+		unique = append(unique, file)
+	}
+
+	if len(unique) == 0 {
+		return nil, ErrNoBundleFiles
+	}
+	return unique, nil
+}
+
+// cleanBundlePath normalizes a bundled file's path to a clean, slash
+// separated one. Whether it stays within a root is checked when applied.
+func cleanBundlePath(name string) (string, error) {
+	name = strings.TrimSpace(strings.ReplaceAll(name, "\\", "/"))
+	if name == "" {
+		return "", errors.New("empty file path in the bundle")
+	}
+	return path.Clean(name), nil
+}
+
+// parseJSONBundle parses the json format, an array of {path, content}
+// objects or a {tree, files} object. Documents that follow each other,
+// as the parts of a bundle do, are parsed one after the other.
+func parseJSONBundle(text string) ([]BundleFile, bool) {
+	trimmed := strings.TrimSpace(text)
+	if !strings.HasPrefix(trimmed, "[") && !strings.HasPrefix(trimmed, "{") {
+		return nil, false
+	}
+
+	var files []BundleFile
+	decoder := json.NewDecoder(strings.NewReader(trimmed))
+	for {
+		var value json.RawMessage
+		err := decoder.Decode(&value)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, false
+		}
+		if strings.HasPrefix(string(value), "{") {
+			var bundle jsonBundle
+			if err := json.Unmarshal(value, &bundle); err != nil {
+				return nil, false
+			}
+			files = append(files, bundle.Files...)
+			continue
+		}
+		var part []BundleFile
+		if err := json.Unmarshal(value, &part); err != nil {
+			return nil, false
+		}
+		files = append(files, part...)
+	}
+
+	return files, len(files) > 0
+}
+
+// parseTextBundle parses the plain, markdown and xml formats
+// and the fenced code block variants.
+func parseTextBundle(text string) []BundleFile {
+	lines := strings.Split(text, "\n")
+	var files []BundleFile
+
+	for i := 0; i < len(lines); {
+		line := lines[i]
+
+		if m := plainHeader.FindStringSubmatch(line); m != nil {
+			fence := i + 1
+			for fence < len(lines) && strings.TrimSpace(lines[fence]) == "" {
+				fence++
+			}
+			// A fenced section ends with its code block, while
+			// a bare one runs until the next header.
+			if fence < len(lines) && fenceOpen.MatchString(lines[fence]) {
+				content, end := fencedContent(lines, fence)
+				files = append(files, BundleFile{Path: m[1], Content: content})
+				i = end
+				continue
+			}
+			start := i + 1
+			if start < len(lines) && lines[start] == "" {
+				start++ // The blank line after the header
+			}
+			end := start
+			for end < len(lines) && !plainHeader.MatchString(lines[end]) {
+				end++
+			}
+			files = append(files, BundleFile{Path: m[1], Content: plainContent(lines[start:end], end == len(lines))})
+			i = end
+			continue
+		}
+
+		if m := xmlHeader.FindStringSubmatch(line); m != nil {
+			// Contents without a CDATA section, as a chat may
+			// write them, run until the first "</file>" line.
+			closing := "</file>"
+			if m[2] != "" {
+				closing = cdataClose + closing
+			}
+			end := i + 1
+			for end < len(lines) && lines[end] != closing {
+				end++
+			}
+			content := joinLines(lines[i+1 : end])
+			if m[2] != "" {
+				content = strings.ReplaceAll(content, cdataSplit, cdataClose)
+			}
+			files = append(files, BundleFile{Path: html.UnescapeString(m[1]), Content: content})
+			i = end + 1
+			continue
+		}
+
+		if m := pathHeading.FindStringSubmatch(strings.TrimSpace(line)); m != nil && looksLikePath(m[1]) {
+			start := i + 1
+			for start < len(lines) && strings.TrimSpace(lines[start]) == "" {
+				start++
+			}
+			if start < len(lines) && fenceOpen.MatchString(lines[start]) {
+				content, end := fencedContent(lines, start)
+				files = append(files, BundleFile{Path: m[1], Content: content})
+				i = end
+				continue
+			}
+		}
+
+		if m := fenceOpen.FindStringSubmatch(line); m != nil {
+			content, end := fencedContent(lines, i)
+			if name := fenceInfoPath(m[2]); name != "" {
+				files = append(files, BundleFile{Path: name, Content: content})
+			}
+			i = end // Code blocks without a path are skipped whole
+			continue
+		}
+
+		i++
+	}
+
+	return files
+}
+
+// plainContent returns the contents of a bare plain format section exactly,
+// as the plain format lays them out between the blank line after their
+// header and the "\n\n" before the next header, if not the last.
+func plainContent(lines []string, last bool) string {
+	content := strings.Join(lines, "\n")
+	if last {
+		return content
+	}
+	return strings.TrimSuffix(content+"\n", "\n\n")
+}
+
+// fencedContent returns the contents of the code block opened at
+// lines[open] and the index of the line after its closing fence.
+// An unclosed block runs to the end of the lines.
+func fencedContent(lines []string, open int) (string, int) {
+	for i := open + 1; i < len(lines); i++ {
+		if fenceCloses(lines, open, i) {
+			return joinLines(lines[open+1 : i]), i + 1
+		}
+	}
+	return joinLines(lines[open+1:]), len(lines)
+}
+
+// fenceCloses tells whether lines[i] closes the code block opened at lines[open],
+// being a fence of the same character, at least as long.
+func fenceCloses(lines []string, open int, i int) bool {
+	fence := fenceOpen.FindStringSubmatch(lines[open])[1]
+	closing := strings.TrimSpace(lines[i])
+	return strings.HasPrefix(closing, fence) && strings.Trim(closing, fence[:1]) == ""
+}
+
+// fenceInfoPath returns the path named in a code block's info string,
+// or an empty string when it only names a language.
+func fenceInfoPath(info string) string {
+	for _, field := range strings.Fields(info) {
+		if m := infoPath.FindStringSubmatch(field); m != nil && looksLikePath(m[1]) {
+			return m[1]
+		}
+	}
+	return ""
+}
+
+// looksLikePath tells whether a name is likely a file path rather than a word
+// or a language, having a directory or an extension.
+func looksLikePath(name string) bool {
+	if strings.Contains(name, "/") {
+		return true
+	}
+	dot := strings.LastIndex(name, ".")
+	return dot >= 0 && dot < len(name)-1
+}
+
+// joinLines joins lines back into contents ending with a line break.
+func joinLines(lines []string) string {
+	if len(lines) == 0 {
+		return ""
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
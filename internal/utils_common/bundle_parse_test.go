@@ -0,0 +1,102 @@
+package utils_common
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+func TestParseBundle_Round_Trips_Every_Format(t *testing.T) {
+	files := []BundleFile{
+		{Path: "main.go", Content: "package main\n\nfunc main() {}\n"},
+		{Path: "docs/README.md", Content: "# Title\n\n```sh\nmake\n```\n"},
+	}
+
+	for _, format := range []string{FormatPlain, FormatMarkdown, FormatXML, FormatJSON} {
+		t.Run(format, func(t *testing.T) {
+			formatter, err := NewFormatter(format)
+			require.NoError(t, err)
+
+			text := formatter.FormatBundle("", "├── main.go\n", files)
+
+			parsed, err := ParseBundle(text)
+			require.NoError(t, err)
+			assert.Equal(t, files, parsed)
+		})
+	}
+}
+
+func TestParseBundle_XML_Round_Trips_Markup(t *testing.T) {
+	files := []BundleFile{
+		{Path: "a.xml", Content: "<file path=\"b\">\n</file>\n]]></file>\n<![CDATA[x]]>\n"},
+		{Path: "b.go", Content: "package b\n"},
+	}
+
+	text := (&xmlFormatter{}).Format(files)
+	assert.Equal(t, 2, strings.Count(text, "]]></file>\n"), "a file holds a single CDATA section closing")
+
+	parsed, err := ParseBundle(text)
+	require.NoError(t, err)
+	assert.Equal(t, files, parsed)
+}
+
+func TestParseBundle_Plain_Keeps_Contents_Exactly(t *testing.T) {
+	testCases := map[string][]BundleFile{
+		"Trailing_Blank_Lines": {
+			{Path: "a.txt", Content: "a\n\n\n"},
+			{Path: "b.txt", Content: "b\n\n"},
+		},
+		"No_Final_Newline": {
+			{Path: "a.txt", Content: "no newline"},
+			{Path: "b.txt", Content: "last, no newline"},
+		},
+		"Leading_Blank_Lines": {
+			{Path: "a.txt", Content: "\n\nstarts blank\n"},
+			{Path: "empty.txt", Content: ""},
+			{Path: "b.txt", Content: "\n"},
+		},
+	}
+
+	for name, files := range testCases {
+		t.Run(name, func(t *testing.T) {
+			parsed, err := ParseBundle((&plainFormatter{}).FormatBundle("part 1/2", ".\n", files))
+			require.NoError(t, err)
+			assert.Equal(t, files, parsed)
+		})
+	}
+}
+
+func TestParseBundle_Chat_Variants(t *testing.T) {
+	text := "Sure, here are the changes.\n\n" +
+		"--- pkg/a.go ---\n\n```go\npackage pkg\n```\n\nThen a new file:\n\n" +
+		"**pkg/b.go**\n```go\npackage pkg // b\n```\n\n" +
+		"File: `pkg/c.go`\n\n~~~go\npackage pkg // c\n~~~\n\n" +
+		"```go:pkg/d.go\npackage pkg // d\n```\n\n" +
+		"```go title=\"pkg/e.go\"\npackage pkg // e\n```\n\n" +
+		"### Example\n\n```sh\ngo test ./...\n```\n\n" +
+		"```go\n--- not/a/header.go ---\n```\n" +
+		"Let me know if anything else is needed!\n"
+
+	parsed, err := ParseBundle(text)
+	require.NoError(t, err)
+
+	assert.Equal(t, []BundleFile{
+		{Path: "pkg/a.go", Content: "package pkg\n"},
+		{Path: "pkg/b.go", Content: "package pkg // b\n"},
+		{Path: "pkg/c.go", Content: "package pkg // c\n"},
+		{Path: "pkg/d.go", Content: "package pkg // d\n"},
+		{Path: "pkg/e.go", Content: "package pkg // e\n"},
+	}, parsed)
+}
+
+func TestParseBundle_Last_Duplicate_Wins(t *testing.T) {
+	parsed, err := ParseBundle("--- ./a.txt ---\n\nold\n\n--- a.txt ---\n\nnew\r\n")
+	require.NoError(t, err)
+	assert.Equal(t, []BundleFile{{Path: "a.txt", Content: "new\n"}}, parsed)
+}
+
+func TestParseBundle_No_Files(t *testing.T) {
+	_, err := ParseBundle("Just some prose.\n\n```go\nfmt.Println()\n```\n")
+	require.ErrorIs(t, err, ErrNoBundleFiles)
+}
@@ -0,0 +1,49 @@
+package utils_common
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestBuildBundle_Redacts_Secrets(t *testing.T) {
+	arrTestDir, cleanup := testCreateTestDir(t)
+	defer cleanup()
+
+	root := filepath.Join(arrTestDir...)
+
+	err := os.WriteFile(filepath.Join(root, "app.env"), []byte("DB_USER=demby\nDB_PASS=hunter22\n"), 0644)
+	require.NoError(t, err, "write app.env")
+
+	bundle, err := BuildBundle(context.Background(), &ClipOptions{Paths: []string{root}})
+	require.NoError(t, err, "build bundle")
+
+	assert.Contains(t, bundle.String(), "DB_PASS=[REDACTED]")
+	assert.NotContains(t, bundle.String(), "hunter22")
+	assert.Equal(t, []Redaction{{Path: "app.env", Line: 2, Kind: secretKindAssignment}}, bundle.Result.Redacted)
+
+	bundle, err = BuildBundle(context.Background(), &ClipOptions{Paths: []string{root}, NoRedact: true})
+	require.NoError(t, err, "build bundle without redaction")
+
+	assert.Contains(t, bundle.String(), "DB_PASS=hunter22")
+	assert.Empty(t, bundle.Result.Redacted)
+}
+
+func TestBuildBundle_Line_Numbers(t *testing.T) {
+	arrTestDir, cleanup := testCreateTestDir(t)
+	defer cleanup()
+
+	root := filepath.Join(arrTestDir...)
+
+	err := os.WriteFile(filepath.Join(root, "main.go"), []byte("package main\n\nfunc main() {}\n"), 0644)
+	require.NoError(t, err, "write main.go")
+
+	for _, format := range []string{"plain", "markdown", "xml"} {
+		bundle, err := BuildBundle(context.Background(), &ClipOptions{Paths: []string{root}, Format: format, LineNumbers: true})
+		require.NoError(t, err, "build bundle")
+		assert.Contains(t, bundle.String(), "1 | package main\n2 |\n3 | func main() {}\n", format)
+	}
+}
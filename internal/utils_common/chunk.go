@@ -0,0 +1,78 @@
+package utils_common
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// chunkFiles formats the files into as few chunks as possible while
+// keeping each chunk within maxTokens. Files are never split, so a
+// single file over the budget gets a chunk of its own.
+//
+// The tree, if any, opens the first chunk. When there are several, every
+// chunk is marked as a part (e.g "part 1/3") within its format and the
+// widest marker is counted in the budget. A maxTokens of zero or less
+// disables the budget.
+func chunkFiles(files []BundleFile, formatter Formatter, maxTokens int, tree string) []string {
+	if maxTokens <= 0 {
+		return []string{formatter.FormatBundle("", tree, files)}
+	}
+
+	widest := strconv.Itoa(len(files))
+	marker := chunkMarker(widest, widest)
+	overhead := EstimateTokens(formatter.FormatBundle(marker, "", nil))
+
+	var (
+		groups        [][]BundleFile
+		current       []BundleFile
+		currentTokens = overhead
+	)
+	if tree != "" {
+		currentTokens = EstimateTokens(formatter.FormatBundle(marker, tree, nil))
+	}
+
+	for _, file := range files {
+		// Files are laid out one after the other, so they add up to their
+		// tokens as the first file or as any following one, which also
+		// counts the separator between the files of the format.
+		one := EstimateTokens(formatter.FormatBundle(marker, "", []BundleFile{file}))
+		firstTokens := one - overhead
+		nextTokens := EstimateTokens(formatter.FormatBundle(marker, "", []BundleFile{file, file})) - one
+
+		if len(current) > 0 {
+			if currentTokens+nextTokens <= maxTokens {
+				current = append(current, file)
+				currentTokens += nextTokens
+				continue
+			}
+			groups = append(groups, current)
+			current = nil
+			currentTokens = overhead
+		}
+		current = append(current, file)
+		currentTokens += firstTokens
+	}
+
+	if len(current) > 0 || len(groups) == 0 {
+		groups = append(groups, current)
+	}
+
+	chunks := make([]string, 0, len(groups))
+	for i, group := range groups {
+		var marker, groupTree string
+		if len(groups) > 1 {
+			marker = chunkMarker(strconv.Itoa(i+1), strconv.Itoa(len(groups)))
+		}
+		if i == 0 {
+			groupTree = tree
+		}
+		chunks = append(chunks, formatter.FormatBundle(marker, groupTree, group))
+	}
+
+	return chunks
+}
+
+// chunkMarker names a part of a bundle split by a token budget.
+func chunkMarker(part, total string) string {
+	return fmt.Sprintf("part %s/%s", part, total)
+}
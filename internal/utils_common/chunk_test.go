@@ -0,0 +1,116 @@
+package utils_common
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+func TestEstimateTokens(t *testing.T) {
+	assert.Equal(t, 0, EstimateTokens(""))
+	assert.Equal(t, 1, EstimateTokens("func"))
+	assert.Equal(t, 4, EstimateTokens("hello world"))
+	assert.Equal(t, 7, EstimateTokens("func main() {}\n"))
+	assert.Equal(t, 3, EstimateTokens("CopyRootPath"))
+}
+
+func Test_chunkFiles_No_Budget(t *testing.T) {
+	files := []BundleFile{{Path: "a", Content: "1"}, {Path: "b", Content: "2"}}
+
+	chunks := chunkFiles(files, &plainFormatter{}, 0, "")
+	require.Len(t, chunks, 1)
+	assert.Equal(t, "\n\n--- a ---\n\n1\n\n--- b ---\n\n2", chunks[0])
+}
+
+func Test_chunkFiles_Packs_On_File_Boundaries(t *testing.T) {
+	files := []BundleFile{
+		{Path: "a", Content: strings.Repeat("word ", 5)},
+		{Path: "b", Content: strings.Repeat("word ", 5)},
+		{Path: "c", Content: strings.Repeat("word ", 5)},
+	}
+	formatter := &plainFormatter{}
+
+	// Each formatted file is 5 words and 11 header tokens,
+	// while the "[part 3/3]" marker is counted once per chunk.
+	chunks := chunkFiles(files, formatter, 40, "")
+	require.Len(t, chunks, 2)
+	assert.Equal(t, "[part 1/2]\n"+formatter.Format(files[:2]), chunks[0])
+	assert.Equal(t, "[part 2/2]\n"+formatter.Format(files[2:]), chunks[1])
+}
+
+func Test_chunkFiles_Oversized_File(t *testing.T) {
+	files := []BundleFile{
+		{Path: "a", Content: "small"},
+		{Path: "b", Content: strings.Repeat("word ", 50)},
+		{Path: "c", Content: "small"},
+	}
+
+	chunks := chunkFiles(files, &plainFormatter{}, 10, "")
+	require.Len(t, chunks, 3)
+	assert.Contains(t, chunks[1], files[1].Content)
+}
+
+func Test_chunkFiles_Keeps_Each_Chunk_Well_Formed(t *testing.T) {
+	files := []BundleFile{
+		{Path: "a", Content: strings.Repeat("word ", 20)},
+		{Path: "b", Content: strings.Repeat("word ", 20)},
+	}
+
+	chunks := chunkFiles(files, &jsonFormatter{}, 40, "tree\n")
+	require.Len(t, chunks, 2)
+	for i, chunk := range chunks {
+		var decoded jsonBundle
+		err := json.Unmarshal([]byte(chunk), &decoded)
+		require.NoError(t, err, "chunk %d should be a single JSON document", i)
+		assert.Equal(t, fmt.Sprintf("part %d/2", i+1), decoded.Part)
+		assert.Equal(t, files[i:i+1], decoded.Files)
+	}
+
+	parsed, err := ParseBundle(strings.Join(chunks, ""))
+	require.NoError(t, err)
+	assert.Equal(t, files, parsed)
+}
+
+func Test_chunkFiles_Marks_Parts_Within_The_Format(t *testing.T) {
+	files := []BundleFile{
+		{Path: "a", Content: strings.Repeat("word ", 20)},
+		{Path: "b", Content: strings.Repeat("word ", 20)},
+	}
+
+	markdown := chunkFiles(files, &markdownFormatter{}, 40, "")
+	require.Len(t, markdown, 2)
+	assert.True(t, strings.HasPrefix(markdown[1], "<!-- part 2/2 -->\n\n### b\n"), markdown[1])
+
+	xml := chunkFiles(files, &xmlFormatter{}, 40, "")
+	require.Len(t, xml, 2)
+	assert.True(t, strings.HasPrefix(xml[1], "<!-- part 2/2 -->\n<file path=\"b\"><![CDATA[\n"), xml[1])
+}
+
+func Test_chunkFiles_Counts_The_Separators(t *testing.T) {
+	files := []BundleFile{
+		{Path: "a", Content: strings.Repeat("word ", 5)},
+		{Path: "b", Content: strings.Repeat("word ", 5)},
+	}
+
+	for _, formatter := range []Formatter{&plainFormatter{}, &markdownFormatter{}, &xmlFormatter{}, &jsonFormatter{}} {
+		// One token short of both files, as laid out together.
+		budget := EstimateTokens(formatter.FormatBundle(chunkMarker("2", "2"), "", files)) - 1
+
+		chunks := chunkFiles(files, formatter, budget, "")
+		require.Len(t, chunks, 2, "%T", formatter)
+		for _, chunk := range chunks {
+			assert.LessOrEqual(t, EstimateTokens(chunk), budget, "%T", formatter)
+		}
+	}
+}
+
+func Test_chunkFiles_Fits_In_One(t *testing.T) {
+	files := []BundleFile{{Path: "a", Content: "1"}}
+
+	chunks := chunkFiles(files, &plainFormatter{}, 100, "")
+	require.Len(t, chunks, 1)
+	assert.NotContains(t, chunks[0], "[part", "a single part has no part header")
+}
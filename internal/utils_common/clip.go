@@ -0,0 +1,180 @@
+package utils_common
+
+import (
+	"errors"
+	"strings"
+)
+
+const (
+	// BinaryPlaceholder replaces a binary file's contents with a one-line summary.
+	BinaryPlaceholder = "placeholder"
+	// BinarySkip leaves binary files out of the contents entirely.
+	BinarySkip = "skip"
+)
+
+// ClipOptions are the settings used to bundle the file contents of any mix
+// of files and directories, along with where the bundle is written to.
+type ClipOptions struct {
+	// Paths are the files and directories to clip.
+	Paths []string `mapstructure:"paths" validate:"required_without_all=Profile GoPackages" json:"paths"`
+	// Profile names a clip profile of the configuration, whose root stands
+	// in for the paths when none are given.
+	Profile string `mapstructure:"profile" json:"profile"`
+	// Exclusions are path prefixes left out of the walk.
+	Exclusions []string `mapstructure:"exclusions" json:"exclusions"`
+	// Include only keeps the files matching these doublestar globs,
+	// relative to the paths' common base.
+	Include []string `mapstructure:"include" json:"include"`
+	// Exclude leaves out the files matching these doublestar globs.
+	Exclude []string `mapstructure:"exclude" json:"exclude"`
+	// ForceInclude lets Include override the default exclusions
+	// (Exclusions and ignore files).
+	ForceInclude bool `mapstructure:"force_include" json:"force_include"`
+	// Binary sets how binary files are bundled ("placeholder" or "skip").
+	Binary string `mapstructure:"binary" validate:"omitempty,oneof=placeholder skip" json:"binary"`
+	// MaxTokens splits the contents into parts of at most this many
+	// estimated tokens, zero keeping them whole.
+	MaxTokens int `mapstructure:"max_tokens" json:"max_tokens"`
+	// MaxFileBytes truncates the files over this size, zero being no limit.
+	MaxFileBytes int64 `mapstructure:"max_file_bytes" json:"max_file_bytes"`
+	// MaxTotalBytes truncates the largest files until the contents fit
+	// in this size, zero being no limit.
+	MaxTotalBytes int64 `mapstructure:"max_total_bytes" json:"max_total_bytes"`
+	// Format is the layout of the contents (plain, markdown, xml or json).
+	Format string `mapstructure:"format" validate:"omitempty,oneof=plain markdown xml json" json:"format"`
+	// Stdout writes the bundle to stdout instead of the clipboard.
+	Stdout bool `mapstructure:"stdout" json:"stdout"`
+	// OutFile writes the bundle to this file instead of the clipboard.
+	OutFile string `mapstructure:"out_file" json:"out_file"`
+	// Tree prepends a directory tree of the bundled files.
+	Tree bool `mapstructure:"tree" json:"tree"`
+	// TreeOnly bundles the tree alone.
+	TreeOnly bool `mapstructure:"tree_only" json:"tree_only"`
+	// GitChanged only clips the files changed in the working tree.
+	GitChanged bool `mapstructure:"git_changed" json:"git_changed"`
+	// GitStaged only clips the staged files.
+	GitStaged bool `mapstructure:"git_staged" json:"git_staged"`
+	// GitSince only clips the files changed since the branch forked from
+	// this ref and the uncommitted ones.
+	GitSince string `mapstructure:"git_since" json:"git_since"`
+	// NoRedact keeps the secrets in the contents instead of masking them.
+	NoRedact bool `mapstructure:"no_redact" json:"no_redact"`
+	// LineNumbers prefixes every line of text files with its number.
+	LineNumbers bool `mapstructure:"line_numbers" json:"line_numbers"`
+	// Outline reduces Go files to their declarations and signatures.
+	Outline bool `mapstructure:"outline" json:"outline"`
+	// Minify strips comments and blank lines by each file's language.
+	Minify bool `mapstructure:"minify" json:"minify"`
+	// DryRun builds the bundle without writing it anywhere.
+	DryRun bool `mapstructure:"dry_run" json:"dry_run"`
+	// Sort orders the files listed by a dry run (path, size or tokens).
+	Sort string `mapstructure:"sort" validate:"omitempty,oneof=path size tokens" json:"sort"`
+	// Symlinks sets how symbolic links below the paths are handled
+	// (skip, follow or preserve), followed by default.
+	Symlinks string `mapstructure:"symlinks" validate:"omitempty,oneof=skip follow preserve" json:"symlinks"`
+	// GoPackages replaces the paths with the files of these Go packages
+	// (directories or import paths of the module), in dependency order.
+	GoPackages []string `mapstructure:"go_packages" json:"go_packages"`
+	// Deps adds the packages of the module the GoPackages import.
+	Deps bool `mapstructure:"deps" json:"deps"`
+	// DepsDepth limits Deps to this many levels, zero being no limit.
+	DepsDepth int `mapstructure:"deps_depth" validate:"gte=0" json:"deps_depth"`
+	// NoTests leaves the GoPackages' _test.go files out.
+	NoTests bool `mapstructure:"no_tests" json:"no_tests"`
+	// Symbol clips a Go identifier (e.g "Service.CopyDirToAnother") instead
+	// of the files: its declaration, the types it uses and its call sites.
+	Symbol string `mapstructure:"symbol" json:"symbol"`
+	// SymbolContext is the number of lines clipped around every call site.
+	SymbolContext int `mapstructure:"symbol_context" validate:"gte=0" json:"symbol_context"`
+	// Archive packages the selected files into this zip or tar.gz file.
+	Archive string `mapstructure:"archive" json:"archive"`
+	// SplitBy writes one bundle per "dir", "package" or "size:<bytes>".
+	SplitBy string `mapstructure:"split_by" json:"split_by"`
+	// OutDir is the directory the split bundles and their index go to.
+	OutDir string `mapstructure:"out_dir" json:"out_dir"`
+}
+
+func (c *ClipOptions) Validate() error {
+	if err := ValidateStruct(c); err != nil {
+		return err
+	}
+	if c.Stdout && c.OutFile != "" {
+		return errors.New("'stdout' and 'out_file' cannot be used together")
+	}
+	modes := 0
+	for _, set := range []bool{c.GitChanged, c.GitStaged, c.GitSince != ""} {
+		if set {
+			modes++
+		}
+	}
+	if modes > 1 {
+		return errors.New("only one of 'git_changed', 'git_staged' and 'git_since' can be used")
+	}
+	if strings.HasPrefix(c.GitSince, "-") {
+		return errors.New("'git_since' must be a ref, not an option")
+	}
+	if len(c.GoPackages) > 0 {
+		if len(c.Paths) > 0 {
+			return errors.New("'paths' and 'go_packages' cannot be used together")
+		}
+		if modes > 0 {
+			return errors.New("'go_packages' cannot be used with the git modes")
+		}
+	} else if c.Deps || c.DepsDepth > 0 || c.NoTests {
+		return errors.New("'deps', 'deps_depth' and 'no_tests' require 'go_packages'")
+	}
+	if c.Symbol != "" && (c.Outline || c.Minify) {
+		return errors.New("'symbol' cannot be used with 'outline' or 'minify'")
+	}
+	if c.Archive != "" {
+		if _, err := archiveFormat(c.Archive); err != nil {
+			return err
+		}
+		if c.Stdout || c.OutFile != "" || c.DryRun || c.Symbol != "" {
+			return errors.New("'archive' cannot be used with 'stdout', 'out_file', 'dry_run' or 'symbol'")
+		}
+	}
+	if c.SplitBy != "" {
+		if _, err := parseSplitBy(c.SplitBy); err != nil {
+			return err
+		}
+		if c.OutDir == "" && !c.DryRun {
+			return errors.New("'split_by' requires 'out_dir'")
+		}
+		if c.Stdout || c.OutFile != "" || c.Archive != "" || c.MaxTokens > 0 || c.TreeOnly {
+			return errors.New("'split_by' cannot be used with 'stdout', 'out_file', 'archive', 'max_tokens' or 'tree_only'")
+		}
+	} else if c.OutDir != "" {
+		return errors.New("'out_dir' requires 'split_by'")
+	}
+	return nil
+}
+
+// gitMode tells whether the files are picked by git instead of a walk.
+func (c *ClipOptions) gitMode() bool {
+	return c.GitChanged || c.GitStaged || c.GitSince != ""
+}
+
+// SkippedFile is a file whose contents were left out of the bundle.
+type SkippedFile struct {
+	Path   string `json:"path"`
+	Reason string `json:"reason"`
+}
+
+// ClipResult summarizes what was bundled, Tokens being an estimate. Every
+// path is relative to the Base directory: the Files, the Redacted secrets,
+// the Minified and Truncated files, the Largest contributors to the bundle,
+// the Stats of every bundled file and the files Excluded by the filter.
+type ClipResult struct {
+	Base      string             `json:"base"`
+	Files     []string           `json:"files"`
+	Skipped   []SkippedFile      `json:"skipped"`
+	Excluded  []SkippedFile      `json:"excluded"`
+	Redacted  []Redaction        `json:"redacted"`
+	Minified  []MinifiedFile     `json:"minified"`
+	Truncated []TruncatedFile    `json:"truncated"`
+	Largest   []FileContribution `json:"largest"`
+	Stats     []FileStat         `json:"stats"`
+	Tokens    int                `json:"tokens"`
+	Chunks    int                `json:"chunks"`
+}
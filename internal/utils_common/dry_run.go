@@ -0,0 +1,114 @@
+package utils_common
+
+import (
+	"fmt"
+	"github.com/dembygenesis/local.tools/internal/lib/doc_generator"
+	"sort"
+	"strings"
+)
+
+const (
+	// SortByPath lists the files in the order they are bundled in.
+	SortByPath = "path"
+	// SortBySize lists the largest files first.
+	SortBySize = "size"
+	// SortByTokens lists the files with the most tokens first.
+	SortByTokens = "tokens"
+)
+
+// FileStat is the size of a file's contents in the bundle,
+// Tokens including the file's header.
+type FileStat struct {
+	Path   string `json:"path"`
+	Bytes  int64  `json:"bytes"`
+	Lines  int    `json:"lines"`
+	Tokens int    `json:"tokens"`
+}
+
+// fileStats measures the bundled files, as laid out by the formatter.
+func fileStats(files []BundleFile, formatter Formatter) []FileStat {
+	stats := make([]FileStat, 0, len(files))
+	for _, file := range files {
+		stats = append(stats, FileStat{
+			Path:   file.Path,
+			Bytes:  int64(len(file.Content)),
+			Lines:  countLines(file.Content),
+			Tokens: EstimateTokens(formatter.Format([]BundleFile{file})),
+		})
+	}
+	return stats
+}
+
+// countLines counts the lines of s, including a last unterminated one.
+func countLines(s string) int {
+	n := strings.Count(s, "\n")
+	if s != "" && !strings.HasSuffix(s, "\n") {
+		n++
+	}
+	return n
+}
+
+// FormatDryRun renders the files a bundle would include, with their size,
+// line count and estimated tokens, plus the totals and the skipped files
+// as markdown tables, sorted by path (the default), size or tokens. A file
+// is either included or skipped: a binary file laid out as a placeholder is
+// only counted as included.
+func FormatDryRun(result *ClipResult, sortBy string) string {
+	stats := append([]FileStat(nil), result.Stats...)
+	switch sortBy {
+	case SortBySize:
+		sort.SliceStable(stats, func(i, j int) bool { return stats[i].Bytes > stats[j].Bytes })
+	case SortByTokens:
+		sort.SliceStable(stats, func(i, j int) bool { return stats[i].Tokens > stats[j].Tokens })
+	}
+
+	table := [][]string{{"File", "Size", "Lines", "Tokens"}}
+	var total FileStat
+	for _, stat := range stats {
+		table = append(table, []string{
+			escapeTableCell(stat.Path),
+			FormatBytes(stat.Bytes),
+			formatCount(stat.Lines),
+			formatCount(stat.Tokens),
+		})
+		total.Bytes += stat.Bytes
+		total.Lines += stat.Lines
+		total.Tokens += stat.Tokens
+	}
+	table = append(table, []string{
+		fmt.Sprintf("**Total** (%d files)", len(stats)),
+		FormatBytes(total.Bytes),
+		formatCount(total.Lines),
+		formatCount(total.Tokens),
+	})
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Files that would be clipped from %s:\n\n", result.Base)
+	sb.WriteString(doc_generator.FormatAsMDTable(table))
+	sb.WriteString("\n")
+
+	included := make(map[string]bool, len(stats))
+	for _, stat := range stats {
+		included[stat.Path] = true
+	}
+
+	skipped := [][]string{{"Skipped file", "Reason"}}
+	for _, file := range append(append([]SkippedFile(nil), result.Skipped...), result.Excluded...) {
+		if included[file.Path] {
+			continue
+		}
+		skipped = append(skipped, []string{escapeTableCell(file.Path), escapeTableCell(file.Reason)})
+	}
+	if len(skipped) > 1 {
+		sb.WriteString("\n")
+		sb.WriteString(doc_generator.FormatAsMDTable(skipped))
+		sb.WriteString("\n")
+	}
+
+	return sb.String()
+}
+
+// escapeTableCell escapes the pipes of a markdown table cell.
+func escapeTableCell(s string) string {
+	return strings.ReplaceAll(s, "|", `\|`)
+}
@@ -0,0 +1,86 @@
+package utils_common
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestFormatDryRun(t *testing.T) {
+	result := &ClipResult{
+		Base: "module",
+		Stats: []FileStat{
+			{Path: "a.go", Bytes: 100, Lines: 10, Tokens: 40},
+			{Path: "b.json", Bytes: 2048, Lines: 1200, Tokens: 900},
+		},
+		Skipped: []SkippedFile{
+			{Path: "logo.png", Reason: "[binary, 34 KB, image/png]"},
+		},
+	}
+
+	expected := "Files that would be clipped from module:\n\n" +
+		"| File                | Size   | Lines | Tokens |\n" +
+		"|---------------------|--------|-------|--------|\n" +
+		"| b.json              | 2 KB   | 1,200 | 900    |\n" +
+		"| a.go                | 100 B  | 10    | 40     |\n" +
+		"| **Total** (2 files) | 2.1 KB | 1,210 | 940    |\n" +
+		"\n" +
+		"| Skipped file | Reason                     |\n" +
+		"|--------------|----------------------------|\n" +
+		"| logo.png     | [binary, 34 KB, image/png] |\n"
+
+	assert.Equal(t, expected, FormatDryRun(result, SortBySize))
+}
+
+func TestFormatDryRun_Keeps_Path_Order(t *testing.T) {
+	result := &ClipResult{
+		Base: "module",
+		Stats: []FileStat{
+			{Path: "a.go", Bytes: 1, Lines: 1, Tokens: 1},
+			{Path: "b.go", Bytes: 2, Lines: 1, Tokens: 1},
+		},
+	}
+
+	report := FormatDryRun(result, SortByPath)
+
+	assert.Less(t, strings.Index(report, "a.go"), strings.Index(report, "b.go"))
+	assert.NotContains(t, report, "Skipped file")
+}
+
+func Test_countLines(t *testing.T) {
+	assert.Equal(t, 0, countLines(""))
+	assert.Equal(t, 1, countLines("a"))
+	assert.Equal(t, 1, countLines("a\n"))
+	assert.Equal(t, 2, countLines("a\nb"))
+}
+
+func TestFormatDryRun_Lists_A_File_Once(t *testing.T) {
+	result := &ClipResult{
+		Base: "module",
+		Stats: []FileStat{
+			{Path: "a|b.go", Bytes: 1, Lines: 1, Tokens: 1},
+			{Path: "logo.png", Bytes: 30, Lines: 1, Tokens: 8},
+		},
+		Skipped: []SkippedFile{
+			{Path: "logo.png", Reason: "[binary, 34 KB, image/png]"},
+		},
+		Excluded: []SkippedFile{
+			{Path: "node_modules/", Reason: "ignore file"},
+			{Path: "x|y.log", Reason: "exclude glob '*.log'"},
+		},
+	}
+
+	expected := "Files that would be clipped from module:\n\n" +
+		"| File                | Size | Lines | Tokens |\n" +
+		"|---------------------|------|-------|--------|\n" +
+		"| a\\|b.go             | 1 B  | 1     | 1      |\n" +
+		"| logo.png            | 30 B | 1     | 8      |\n" +
+		"| **Total** (2 files) | 31 B | 2     | 9      |\n" +
+		"\n" +
+		"| Skipped file  | Reason               |\n" +
+		"|---------------|----------------------|\n" +
+		"| node_modules/ | ignore file          |\n" +
+		"| x\\|y.log      | exclude glob '*.log' |\n"
+
+	assert.Equal(t, expected, FormatDryRun(result, SortByPath))
+}
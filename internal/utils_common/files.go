@@ -14,6 +14,7 @@ type CopyOptions struct {
 	Destination               string   `mapstructure:"destination" validate:"required" json:"destination"`
 	WipeDestination           bool     `mapstructure:"wipe_destination" json:"wipe_destination"`
 	WipeDestinationExclusions []string `mapstructure:"wipe_destination_exclusions" json:"wipe_destination_exclusions"`
+	Symlinks                  string   `mapstructure:"symlinks" validate:"omitempty,oneof=skip follow preserve" json:"symlinks"`
 }
 
 func (c *CopyOptions) Validate() error {
@@ -78,7 +79,7 @@ func CopyDirToAnother(opts *CopyOptions) error {
 		logger.Warnf("%d items were deleted", totalDeleted)
 	}
 
-	addedCount, err := copyDir(opts.Source, opts.Destination, opts.SourceExclusions)
+	addedCount, err := copyDir(opts.Source, opts.Destination, opts.SourceExclusions, opts.Symlinks)
 	if err != nil {
 		return fmt.Errorf("copy dir: %w", err)
 	}
@@ -113,7 +114,10 @@ func copyFile(src, dst string) error {
 	return os.Chmod(dst, srcInfo.Mode())
 }
 
-func copyDir(src, dst string, exclusions []string) (int, error) {
+// copyDir copies the tree of src into dst, leaving out the directories
+// ending with one of the exclusions. Symbolic links are handled by the
+// policy, preserved ones being recreated in dst pointing to the same target.
+func copyDir(src, dst string, exclusions []string, symlinks string) (int, error) {
 	addedCount := 0
 	srcInfo, err := os.Stat(src)
 	if err != nil {
@@ -124,38 +128,55 @@ func copyDir(src, dst string, exclusions []string) (int, error) {
 		return 0, fmt.Errorf("source is not a directory")
 	}
 
-	for _, exclusion := range exclusions {
-		if strings.HasSuffix(src, exclusion) {
-			return 0, nil // Skip the excluded directory
+	err = walkTree(src, symlinks, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
 		}
-	}
 
-	if err := os.MkdirAll(dst, srcInfo.Mode()); err != nil {
-		return addedCount, err
-	}
-	addedCount++
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		dstPath := filepath.Join(dst, rel)
+
+		switch {
+		case info.IsDir():
+			for _, exclusion := range exclusions {
+				if strings.HasSuffix(path, exclusion) {
+					return filepath.SkipDir // Skip the excluded directory
+				}
+			}
+			if err := os.MkdirAll(dstPath, info.Mode()); err != nil {
+				return err
+			}
+		case isSymlink(info):
+			if err := copySymlink(path, dstPath); err != nil {
+				return err
+			}
+		default:
+			if err := copyFile(path, dstPath); err != nil {
+				return err
+			}
+		}
+
+		addedCount++
+		return nil
+	})
+
+	return addedCount, err
+}
 
-	entries, err := os.ReadDir(src)
+// copySymlink recreates the symbolic link at src as dst,
+// replacing whatever dst was.
+func copySymlink(src, dst string) error {
+	target, err := os.Readlink(src)
 	if err != nil {
-		return addedCount, err
+		return err
 	}
 
-	for _, entry := range entries {
-		srcPath := filepath.Join(src, entry.Name())
-		dstPath := filepath.Join(dst, entry.Name())
-
-		if entry.IsDir() {
-			dirAddedCount, err := copyDir(srcPath, dstPath, exclusions)
-			if err != nil {
-				return addedCount, err
-			}
-			addedCount += dirAddedCount
-		} else {
-			if err := copyFile(srcPath, dstPath); err != nil {
-				return addedCount, err
-			}
-			addedCount++
-		}
+	if err := os.Remove(dst); err != nil && !os.IsNotExist(err) {
+		return err
 	}
-	return addedCount, nil
+
+	return os.Symlink(target, dst)
 }
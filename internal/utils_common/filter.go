@@ -0,0 +1,242 @@
+package utils_common
+
+import (
+	"fmt"
+	"github.com/bmatcuk/doublestar/v4"
+	"path/filepath"
+	"strings"
+)
+
+// outputReason is why a previous output of the clip is left out.
+const outputReason = "output of the clip"
+
+// fileFilter decides which paths under a root are selected.
+//
+// The precedence, from the highest to the lowest, is:
+//   - exclude globs given by the user
+//   - include globs, over the default exclusions only when forceInclude is set
+//   - default exclusions (configured prefixes and ignore files)
+//   - include globs, as a filter of what remains
+type fileFilter struct {
+	root         string
+	prefixes     []string
+	ignore       *ignoreMatcher
+	include      []string
+	exclude      []string
+	forceInclude bool
+	outputs      map[string]bool // Absolute paths the clip writes to
+	excluded     []SkippedFile   // Files and pruned directories left out, with why
+	seen         map[string]bool
+}
+
+// newFileFilter creates a filter for the root from the clip options.
+func newFileFilter(root string, opts *ClipOptions) (*fileFilter, error) {
+	for _, pattern := range append(append([]string{}, opts.Include...), opts.Exclude...) {
+		if !doublestar.ValidatePattern(pattern) {
+			return nil, fmt.Errorf("invalid glob: %s", pattern)
+		}
+	}
+
+	ignore, err := newIgnoreMatcherForRoot(root)
+	if err != nil {
+		return nil, fmt.Errorf("ignore files: %v", err)
+	}
+
+	// A previous output below the root is never clipped into the next one.
+	outputs := make(map[string]bool)
+	for _, output := range []string{opts.OutFile, opts.Archive, opts.OutDir} {
+		if strings.TrimSpace(output) == "" {
+			continue
+		}
+		abs, err := filepath.Abs(strings.TrimSpace(output))
+		if err != nil {
+			return nil, err
+		}
+		outputs[abs] = true
+	}
+
+	return &fileFilter{
+		root:         root,
+		prefixes:     opts.Exclusions,
+		ignore:       ignore,
+		include:      opts.Include,
+		exclude:      opts.Exclude,
+		forceInclude: opts.ForceInclude,
+		outputs:      outputs,
+		excluded:     make([]SkippedFile, 0),
+		seen:         make(map[string]bool),
+	}, nil
+}
+
+// relative returns the slash separated path relative to the root.
+func (f *fileFilter) relative(path string) (string, error) {
+	rel, err := filepath.Rel(f.root, path)
+	if err != nil {
+		return "", err
+	}
+	return filepath.ToSlash(rel), nil
+}
+
+// defaultExclusion checks the configured prefixes and the ignore files,
+// returning why the path is excluded or an empty string when it is not.
+func (f *fileFilter) defaultExclusion(path, rel string, isDir bool) string {
+	for _, prefix := range f.prefixes {
+		if strings.HasPrefix(strings.TrimSpace(rel), strings.TrimSpace(prefix)) {
+			return fmt.Sprintf("excluded prefix '%s'", strings.TrimSpace(prefix))
+		}
+	}
+	if path != f.root && f.ignore.ignored(path, isDir) {
+		return "ignore file"
+	}
+	return ""
+}
+
+// skip records once why a file or a pruned directory was left out.
+func (f *fileFilter) skip(rel string, isDir bool, reason string) {
+	if isDir {
+		rel += "/"
+	}
+	if f.seen[rel] {
+		return
+	}
+	f.seen[rel] = true
+	f.excluded = append(f.excluded, SkippedFile{Path: rel, Reason: reason})
+}
+
+// pruneDir tells whether a directory's subtree can be skipped entirely.
+// The second value tells whether the directory is excluded by default.
+func (f *fileFilter) pruneDir(path string, parentExcluded bool) (bool, bool, error) {
+	rel, err := f.relative(path)
+	if err != nil {
+		return false, false, err
+	}
+
+	if f.outputs[path] {
+		f.skip(rel, true, outputReason)
+		return true, true, nil
+	}
+
+	if pattern, ok := firstMatch(f.exclude, rel); ok && path != f.root {
+		f.skip(rel, true, fmt.Sprintf("exclude glob '%s'", pattern))
+		return true, true, nil
+	}
+
+	var reason string
+	if path != f.root {
+		reason = f.defaultExclusion(path, rel, true)
+	}
+	excluded := parentExcluded || reason != ""
+	if excluded && !(f.forceInclude && len(f.include) > 0) {
+		f.skip(rel, true, reason)
+		return true, true, nil
+	}
+
+	if !excluded {
+		// Rules of nested ignore files only apply to their own subtree,
+		// and the walk visits a directory before its contents.
+		if err := f.ignore.load(path); err != nil {
+			return false, false, fmt.Errorf("load ignore files of %s: %v", path, err)
+		}
+	}
+
+	return false, excluded, nil
+}
+
+// selectsFile tells whether a file is selected, given
+// whether its parent directory is excluded by default.
+func (f *fileFilter) selectsFile(path string, parentExcluded bool) (bool, error) {
+	rel, err := f.relative(path)
+	if err != nil {
+		return false, err
+	}
+
+	if f.outputs[path] {
+		f.skip(rel, false, outputReason)
+		return false, nil
+	}
+
+	if pattern, ok := firstMatch(f.exclude, rel); ok {
+		f.skip(rel, false, fmt.Sprintf("exclude glob '%s'", pattern))
+		return false, nil
+	}
+
+	reason := f.defaultExclusion(path, rel, false)
+	if parentExcluded || reason != "" {
+		if f.forceInclude && matchesAny(f.include, rel) {
+			return true, nil
+		}
+		if reason == "" {
+			reason = "excluded directory"
+		}
+		f.skip(rel, false, reason)
+		return false, nil
+	}
+
+	return len(f.include) == 0 || matchesAny(f.include, rel), nil
+}
+
+// enter loads the ignore files of the directories between the root
+// and dir, which is about to be walked.
+func (f *fileFilter) enter(dir string) error {
+	rel, err := f.relative(dir)
+	if err != nil {
+		return err
+	}
+
+	current := f.root
+	if err := f.ignore.load(current); err != nil {
+		return err
+	}
+	if rel == "." {
+		return nil
+	}
+
+	for _, segment := range strings.Split(rel, "/") {
+		current = filepath.Join(current, segment)
+		if err := f.ignore.load(current); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// selectsPath tells whether a file found outside of a walk is selected,
+// checking the directories between the root and the file on the way.
+func (f *fileFilter) selectsPath(path string) (bool, error) {
+	rel, err := f.relative(path)
+	if err != nil {
+		return false, err
+	}
+
+	dir, parentExcluded := f.root, false
+	segments := strings.Split(rel, "/")
+	for _, segment := range segments[:len(segments)-1] {
+		dir = filepath.Join(dir, segment)
+		prune, excluded, err := f.pruneDir(dir, parentExcluded)
+		if err != nil {
+			return false, err
+		}
+		if prune {
+			return false, nil
+		}
+		parentExcluded = excluded
+	}
+
+	return f.selectsFile(path, parentExcluded)
+}
+
+func matchesAny(patterns []string, rel string) bool {
+	_, ok := firstMatch(patterns, rel)
+	return ok
+}
+
+// firstMatch returns the first of the patterns matching rel.
+func firstMatch(patterns []string, rel string) (string, bool) {
+	for _, pattern := range patterns {
+		if ok, _ := doublestar.Match(pattern, rel); ok {
+			return pattern, true
+		}
+	}
+	return "", false
+}
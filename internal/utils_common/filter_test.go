@@ -0,0 +1,115 @@
+package utils_common
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// testWalkSelection selects the files of the root with the
+// options and returns them relative to the root.
+func testWalkSelection(t *testing.T, root string, opts *ClipOptions) []string {
+	t.Helper()
+
+	opts.Paths = []string{root}
+	_, files, _, err := selectFiles(context.Background(), opts)
+	require.NoError(t, err, "select files")
+
+	relFiles := make([]string, 0, len(files))
+	for _, file := range files {
+		relFiles = append(relFiles, file.Rel)
+	}
+	return relFiles
+}
+
+func Test_fileFilter(t *testing.T) {
+	arrTestDir, cleanup := testCreateTestDir(t)
+	defer cleanup()
+
+	root := filepath.Join(arrTestDir...)
+
+	testCreateFilesAndFolders(t, arrTestDir, []fileDetail{
+		{Name: ".idea", Type: fileTypeFolder},
+		{Name: filepath.Join(".idea", "workspace.xml"), Type: fileTypeFile},
+		{Name: "internal", Type: fileTypeFolder},
+		{Name: filepath.Join("internal", "cli"), Type: fileTypeFolder},
+		{Name: filepath.Join("internal", "cli", "service.go"), Type: fileTypeFile},
+		{Name: filepath.Join("internal", "cli", "clifakes"), Type: fileTypeFolder},
+		{Name: filepath.Join("internal", "cli", "clifakes", "fake.go"), Type: fileTypeFile},
+		{Name: "main.go", Type: fileTypeFile},
+		{Name: "readme.md", Type: fileTypeFile},
+		{Name: ".env", Type: fileTypeFile},
+	})
+
+	err := os.WriteFile(filepath.Join(root, gitIgnoreFile), []byte(".env\n"), 0644)
+	require.NoError(t, err, "write .gitignore")
+
+	type testCase struct {
+		name     string
+		opts     *ClipOptions
+		expected []string
+	}
+
+	testCases := []testCase{
+		{
+			name: "Defaults",
+			opts: &ClipOptions{Exclusions: []string{".idea"}},
+			expected: []string{
+				".gitignore", "internal/cli/clifakes/fake.go", "internal/cli/service.go", "main.go", "readme.md",
+			},
+		},
+		{
+			name:     "Include",
+			opts:     &ClipOptions{Exclusions: []string{".idea"}, Include: []string{"**/*.go"}},
+			expected: []string{"internal/cli/clifakes/fake.go", "internal/cli/service.go", "main.go"},
+		},
+		{
+			name: "Include And Exclude",
+			opts: &ClipOptions{
+				Exclusions: []string{".idea"},
+				Include:    []string{"**/*.go"},
+				Exclude:    []string{"internal/**/clifakes/**"},
+			},
+			expected: []string{"internal/cli/service.go", "main.go"},
+		},
+		{
+			name:     "Include Does Not Override Defaults",
+			opts:     &ClipOptions{Exclusions: []string{".idea"}, Include: []string{".idea/*.xml", ".env"}},
+			expected: []string{},
+		},
+		{
+			name: "Forced Include Overrides Defaults",
+			opts: &ClipOptions{
+				Exclusions:   []string{".idea"},
+				Include:      []string{".idea/*.xml", ".env", "main.go"},
+				ForceInclude: true,
+			},
+			expected: []string{".env", ".idea/workspace.xml", "main.go"},
+		},
+		{
+			name: "Exclude Wins Over Forced Include",
+			opts: &ClipOptions{
+				Exclusions:   []string{".idea"},
+				Include:      []string{".idea/*.xml", ".env"},
+				Exclude:      []string{".env"},
+				ForceInclude: true,
+			},
+			expected: []string{".idea/workspace.xml"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.ElementsMatch(t, tc.expected, testWalkSelection(t, root, tc.opts))
+		})
+	}
+}
+
+func Test_newFileFilter_Fail_Invalid_Glob(t *testing.T) {
+	_, err := newFileFilter(".", &ClipOptions{Include: []string{"[a-"}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid glob")
+}
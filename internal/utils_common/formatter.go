@@ -0,0 +1,269 @@
+package utils_common
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	FormatPlain    = "plain"
+	FormatMarkdown = "markdown"
+	FormatXML      = "xml"
+	FormatJSON     = "json"
+)
+
+// BundleFile is a file's path and the contents that are bundled for it.
+// Size is the file's size on disk, which can differ from the contents.
+type BundleFile struct {
+	Path    string `json:"path"`
+	Content string `json:"content"`
+	Size    int64  `json:"-"`
+}
+
+// Formatter lays out bundled files into a single string, along with
+// the directory tree overview that can precede them.
+type Formatter interface {
+	// Format lays out the files alone.
+	Format(files []BundleFile) string
+	// FormatBundle lays out a whole document: the marker naming the
+	// part of a bundle (e.g "part 1/3") and the tree, unless empty,
+	// followed by the files.
+	FormatBundle(marker string, tree string, files []BundleFile) string
+}
+
+// NewFormatter returns the built-in formatter registered under the name.
+func NewFormatter(name string) (Formatter, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", FormatPlain:
+		return &plainFormatter{}, nil
+	case FormatMarkdown:
+		return &markdownFormatter{}, nil
+	case FormatXML:
+		return &xmlFormatter{}, nil
+	case FormatJSON:
+		return &jsonFormatter{}, nil
+	}
+	return nil, fmt.Errorf("unknown format: %s", name)
+}
+
+// plainFormatter separates files with a "--- path ---" header.
+type plainFormatter struct{}
+
+func (f *plainFormatter) Format(files []BundleFile) string {
+	var sb strings.Builder
+	for _, file := range files {
+		sb.WriteString(fmt.Sprintf("\n\n--- %s ---\n\n", file.Path))
+		sb.WriteString(file.Content)
+	}
+	return sb.String()
+}
+
+func (f *plainFormatter) FormatTree(tree string) string {
+	return tree
+}
+
+// FormatBundle opens the document with the marker as a "[part 1/3]" line.
+func (f *plainFormatter) FormatBundle(marker string, tree string, files []BundleFile) string {
+	var sb strings.Builder
+	if marker != "" {
+		sb.WriteString("[" + marker + "]\n")
+	}
+	if tree != "" {
+		sb.WriteString(f.FormatTree(tree))
+	}
+	sb.WriteString(f.Format(files))
+	return sb.String()
+}
+
+// markdownFormatter puts every file in a fenced code block,
+// with the language inferred from the file extension.
+type markdownFormatter struct{}
+
+func (f *markdownFormatter) Format(files []BundleFile) string {
+	var sb strings.Builder
+	for i, file := range files {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		fence := markdownFence(file.Content)
+		sb.WriteString(fmt.Sprintf("### %s\n\n", file.Path))
+		sb.WriteString(fence + languageOf(file.Path) + "\n")
+		sb.WriteString(file.Content)
+		if !strings.HasSuffix(file.Content, "\n") {
+			sb.WriteString("\n")
+		}
+		sb.WriteString(fence + "\n")
+	}
+	return sb.String()
+}
+
+func (f *markdownFormatter) FormatTree(tree string) string {
+	return "### Tree\n\n```text\n" + tree + "```\n\n"
+}
+
+// FormatBundle opens the document with the marker as an html comment.
+func (f *markdownFormatter) FormatBundle(marker string, tree string, files []BundleFile) string {
+	var sb strings.Builder
+	if marker != "" {
+		sb.WriteString(commentMarker(marker) + "\n\n")
+	}
+	if tree != "" {
+		sb.WriteString(f.FormatTree(tree))
+	}
+	sb.WriteString(f.Format(files))
+	return sb.String()
+}
+
+// markdownFence returns a backtick fence longer than any backtick run in
+// the content, so the content can never close the code block early.
+func markdownFence(content string) string {
+	longest, run := 0, 0
+	for _, r := range content {
+		if r == '`' {
+			run++
+			if run > longest {
+				longest = run
+			}
+			continue
+		}
+		run = 0
+	}
+	if longest < 3 {
+		return "```"
+	}
+	return strings.Repeat("`", longest+1)
+}
+
+// xmlFormatter wraps every file in a <file path="..."> tag, its contents
+// in a CDATA section, so a "</file>" line in them cannot close the tag.
+type xmlFormatter struct{}
+
+func (f *xmlFormatter) Format(files []BundleFile) string {
+	var sb strings.Builder
+	for _, file := range files {
+		sb.WriteString(fmt.Sprintf("<file path=\"%s\">%s\n", html.EscapeString(file.Path), cdataOpen))
+		sb.WriteString(strings.ReplaceAll(file.Content, cdataClose, cdataSplit))
+		if !strings.HasSuffix(file.Content, "\n") {
+			sb.WriteString("\n")
+		}
+		sb.WriteString(cdataClose + "</file>\n")
+	}
+	return sb.String()
+}
+
+const (
+	cdataOpen  = "<![CDATA["
+	cdataClose = "]]>"
+	// cdataSplit is a "]]>" of the contents, split over two CDATA sections.
+	cdataSplit = "]]]]><![CDATA[>"
+)
+
+func (f *xmlFormatter) FormatTree(tree string) string {
+	return "<tree>\n" + tree + "</tree>\n"
+}
+
+// FormatBundle opens the document with the marker as an xml comment.
+func (f *xmlFormatter) FormatBundle(marker string, tree string, files []BundleFile) string {
+	var sb strings.Builder
+	if marker != "" {
+		sb.WriteString(commentMarker(marker) + "\n")
+	}
+	if tree != "" {
+		sb.WriteString(f.FormatTree(tree))
+	}
+	sb.WriteString(f.Format(files))
+	return sb.String()
+}
+
+// commentMarker puts a marker in an html or xml comment, which
+// cannot hold a "--".
+func commentMarker(marker string) string {
+	return "<!-- " + strings.ReplaceAll(marker, "--", "- -") + " -->"
+}
+
+// jsonFormatter renders the files as a JSON array of {path, content} objects.
+// Along with a marker or the tree, the document is a {part, tree, files}
+// object instead.
+type jsonFormatter struct{}
+
+// jsonBundle is the json format's document when it holds more than the files.
+type jsonBundle struct {
+	Part  string       `json:"part,omitempty"`
+	Tree  string       `json:"tree,omitempty"`
+	Files []BundleFile `json:"files"`
+}
+
+func (f *jsonFormatter) Format(files []BundleFile) string {
+	if files == nil {
+		files = make([]BundleFile, 0)
+	}
+	// Marshalling strings cannot fail.
+	b, _ := json.MarshalIndent(files, "", "  ")
+	return string(b) + "\n"
+}
+
+func (f *jsonFormatter) FormatBundle(marker string, tree string, files []BundleFile) string {
+	if marker == "" && tree == "" {
+		return f.Format(files)
+	}
+	if files == nil {
+		files = make([]BundleFile, 0)
+	}
+	// Marshalling strings cannot fail.
+	b, _ := json.MarshalIndent(jsonBundle{Part: marker, Tree: tree, Files: files}, "", "  ")
+	return string(b) + "\n"
+}
+
+// languages maps file extensions to markdown code block languages.
+var languages = map[string]string{
+	".go":    "go",
+	".mod":   "go",
+	".sum":   "text",
+	".js":    "javascript",
+	".jsx":   "jsx",
+	".mjs":   "javascript",
+	".ts":    "typescript",
+	".tsx":   "tsx",
+	".py":    "python",
+	".rb":    "ruby",
+	".rs":    "rust",
+	".java":  "java",
+	".kt":    "kotlin",
+	".c":     "c",
+	".h":     "c",
+	".cpp":   "cpp",
+	".hpp":   "cpp",
+	".cs":    "csharp",
+	".php":   "php",
+	".swift": "swift",
+	".sh":    "bash",
+	".bash":  "bash",
+	".zsh":   "bash",
+	".sql":   "sql",
+	".json":  "json",
+	".yml":   "yaml",
+	".yaml":  "yaml",
+	".toml":  "toml",
+	".xml":   "xml",
+	".html":  "html",
+	".css":   "css",
+	".scss":  "scss",
+	".md":    "markdown",
+	".proto": "protobuf",
+	".env":   "bash",
+}
+
+// languageOf infers the code block language from the file name.
+func languageOf(path string) string {
+	base := filepath.Base(path)
+	switch base {
+	case "Dockerfile":
+		return "dockerfile"
+	case "Makefile":
+		return "makefile"
+	}
+	return languages[strings.ToLower(filepath.Ext(base))]
+}
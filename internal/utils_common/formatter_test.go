@@ -0,0 +1,82 @@
+package utils_common
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestNewFormatter(t *testing.T) {
+	for _, name := range []string{"", FormatPlain, FormatMarkdown, FormatXML, FormatJSON, "Markdown"} {
+		_, err := NewFormatter(name)
+		assert.NoError(t, err, "format %q should be supported", name)
+	}
+
+	_, err := NewFormatter("yaml")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown format")
+}
+
+func Test_plainFormatter_Format(t *testing.T) {
+	files := []BundleFile{{Path: "a.go", Content: "package a"}}
+	assert.Equal(t, "\n\n--- a.go ---\n\npackage a", (&plainFormatter{}).Format(files))
+}
+
+func Test_markdownFormatter_Format(t *testing.T) {
+	files := []BundleFile{
+		{Path: "main.go", Content: "package main\n"},
+		{Path: "README.md", Content: "```sh\nls\n```"},
+		{Path: "LICENSE", Content: "MIT"},
+	}
+
+	expected := "### main.go\n\n```go\npackage main\n```\n" +
+		"\n### README.md\n\n````markdown\n```sh\nls\n```\n````\n" +
+		"\n### LICENSE\n\n```\nMIT\n```\n"
+
+	assert.Equal(t, expected, (&markdownFormatter{}).Format(files))
+}
+
+func Test_xmlFormatter_Format(t *testing.T) {
+	files := []BundleFile{{Path: `a "b".go`, Content: "package a"}}
+	assert.Equal(t, "<file path=\"a &#34;b&#34;.go\"><![CDATA[\npackage a\n]]></file>\n", (&xmlFormatter{}).Format(files))
+}
+
+func Test_jsonFormatter_Format(t *testing.T) {
+	files := []BundleFile{{Path: "a.go", Content: "package a\n"}}
+
+	var decoded []BundleFile
+	err := json.Unmarshal([]byte((&jsonFormatter{}).Format(files)), &decoded)
+	require.NoError(t, err)
+	assert.Equal(t, files, decoded)
+
+	assert.Equal(t, "[]\n", (&jsonFormatter{}).Format(nil))
+}
+
+func Test_languageOf(t *testing.T) {
+	assert.Equal(t, "go", languageOf("internal/cli/service.go"))
+	assert.Equal(t, "yaml", languageOf("ci.YML"))
+	assert.Equal(t, "dockerfile", languageOf("build/Dockerfile"))
+	assert.Equal(t, "", languageOf("LICENSE"))
+}
+
+func TestFormatter_FormatTree(t *testing.T) {
+	tree := ".\n└── a.go (1 B)\n"
+
+	assert.Equal(t, tree, (&plainFormatter{}).FormatTree(tree))
+	assert.Equal(t, "### Tree\n\n```text\n"+tree+"```\n\n", (&markdownFormatter{}).FormatTree(tree))
+	assert.Equal(t, "<tree>\n"+tree+"</tree>\n", (&xmlFormatter{}).FormatTree(tree))
+}
+
+func Test_jsonFormatter_FormatBundle(t *testing.T) {
+	tree := ".\n└── a.go (1 B)\n"
+	files := []BundleFile{{Path: "a.go", Content: "package a\n"}}
+
+	var decoded jsonBundle
+	err := json.Unmarshal([]byte((&jsonFormatter{}).FormatBundle("", tree, files)), &decoded)
+	require.NoError(t, err, "the tree and the files should be a single JSON document")
+	assert.Equal(t, jsonBundle{Tree: tree, Files: files}, decoded)
+
+	assert.Equal(t, (&jsonFormatter{}).Format(files), (&jsonFormatter{}).FormatBundle("", "", files))
+	assert.JSONEq(t, `{"tree": "x\n", "files": []}`, (&jsonFormatter{}).FormatBundle("", "x\n", nil))
+}
@@ -0,0 +1,148 @@
+package utils_common
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// gitSelection returns the paths of the files selected by the git modes of
+// the options, limited to those under the root and joined to it. Only the local repository
+// is read, so it works offline. Deleted files are left out.
+func gitSelection(root string, opts *ClipOptions) ([]string, error) {
+	top, err := runGit(root, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, fmt.Errorf("find repository: %v", err)
+	}
+	top = strings.TrimSpace(top)
+
+	var relPaths []string
+	switch {
+	case opts.GitChanged, opts.GitStaged:
+		out, err := runGit(root, "status", "--porcelain=v1", "-z", "--untracked-files=all")
+		if err != nil {
+			return nil, fmt.Errorf("git status: %v", err)
+		}
+		relPaths = parseGitStatus(out, opts.GitStaged)
+	case opts.GitSince != "":
+		// The files changed on the branch since it forked from the ref
+		// (its merge base), rather than every difference with the ref,
+		// along with the uncommitted changes. The ref is resolved first,
+		// so it is never taken for an option of git diff.
+		since, err := runGit(root, "rev-parse", "--verify", "--quiet", "--end-of-options", opts.GitSince+"^{commit}")
+		if err != nil {
+			return nil, fmt.Errorf("unknown ref: %s", opts.GitSince)
+		}
+		out, err := runGit(root, "diff", "--name-only", "--diff-filter=d", "-z", strings.TrimSpace(since)+"...HEAD", "--")
+		if err != nil {
+			return nil, fmt.Errorf("git diff %s...HEAD: %v", opts.GitSince, err)
+		}
+		relPaths = splitNul(out)
+
+		status, err := runGit(root, "status", "--porcelain=v1", "-z", "--untracked-files=all")
+		if err != nil {
+			return nil, fmt.Errorf("git status: %v", err)
+		}
+		relPaths = append(relPaths, parseGitStatus(status, false)...)
+	}
+
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+	absRoot, err = filepath.EvalSymlinks(absRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]string, 0, len(relPaths))
+	seen := make(map[string]bool, len(relPaths))
+	for _, relPath := range relPaths {
+		if seen[relPath] {
+			continue // Both committed and changed since
+		}
+		seen[relPath] = true
+
+		abs := filepath.Join(top, filepath.FromSlash(relPath))
+		rel, err := filepath.Rel(absRoot, abs)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue // Outside the root
+		}
+
+		path := filepath.Join(root, rel)
+		if info, err := os.Stat(path); err != nil || info.IsDir() {
+			continue // Deleted or a submodule
+		}
+		files = append(files, path)
+	}
+
+	sort.Strings(files)
+
+	return files, nil
+}
+
+// parseGitStatus extracts the paths from "git status --porcelain=v1 -z".
+// Staged only keeps index changes, otherwise every uncommitted change,
+// including untracked files, is kept.
+func parseGitStatus(out string, staged bool) []string {
+	var paths []string
+
+	entries := splitNul(out)
+	for i := 0; i < len(entries); i++ {
+		entry := entries[i]
+		if len(entry) < 4 {
+			continue
+		}
+		x, y, path := entry[0], entry[1], entry[3:]
+
+		// Renames and copies are followed by their original path.
+		if x == 'R' || x == 'C' {
+			i++
+		}
+
+		if staged {
+			if x == ' ' || x == '?' || x == '!' || x == 'D' {
+				continue
+			}
+		} else if x == 'D' || y == 'D' || x == '!' {
+			continue
+		}
+
+		paths = append(paths, path)
+	}
+
+	return paths
+}
+
+func splitNul(s string) []string {
+	var parts []string
+	for _, part := range strings.Split(s, "\x00") {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return parts
+}
+
+// runGit runs a git command in dir and returns its stdout.
+func runGit(dir string, args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			return "", err
+		}
+		return "", fmt.Errorf("%v: %s", err, msg)
+	}
+
+	return stdout.String(), nil
+}
@@ -0,0 +1,162 @@
+package utils_common
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func Test_parseGitStatus(t *testing.T) {
+	out := " M modified.go\x00M  staged.go\x00MM both.go\x00?? new.go\x00 D deleted.go\x00R  renamed.go\x00old.go\x00"
+
+	assert.Equal(t, []string{"modified.go", "staged.go", "both.go", "new.go", "renamed.go"}, parseGitStatus(out, false))
+	assert.Equal(t, []string{"staged.go", "both.go", "renamed.go"}, parseGitStatus(out, true))
+}
+
+// testGitRepo creates a repository with a single commit in the test dir.
+func testGitRepo(t *testing.T) (root string, cleanup func()) {
+	t.Helper()
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	arrTestDir, cleanup := testCreateTestDir(t)
+	root = filepath.Join(arrTestDir...)
+
+	git := func(args ...string) {
+		_, err := runGit(root, args...)
+		require.NoError(t, err, "git %v", args)
+	}
+
+	git("init", "-q")
+	git("config", "user.email", "test@example.com")
+	git("config", "user.name", "test")
+
+	for _, name := range []string{"a.go", "b.go", "c.go"} {
+		err := os.WriteFile(filepath.Join(root, name), []byte("package a\n"), 0644)
+		require.NoError(t, err, "write %s", name)
+	}
+	git("add", ".")
+	git("commit", "-q", "-m", "init")
+	git("tag", "v1")
+
+	return root, cleanup
+}
+
+func Test_gitSelection(t *testing.T) {
+	root, cleanup := testGitRepo(t)
+	defer cleanup()
+
+	write := func(name string) {
+		err := os.MkdirAll(filepath.Dir(filepath.Join(root, name)), 0755)
+		require.NoError(t, err)
+		err = os.WriteFile(filepath.Join(root, name), []byte("package a\n\n// changed\n"), 0644)
+		require.NoError(t, err, "write %s", name)
+	}
+
+	write("a.go")
+	write("b.go")
+	write(filepath.Join("sub", "new.go"))
+	_, err := runGit(root, "add", "b.go")
+	require.NoError(t, err)
+	require.NoError(t, os.Remove(filepath.Join(root, "c.go")))
+
+	changed, err := gitSelection(root, &ClipOptions{GitChanged: true})
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		filepath.Join(root, "a.go"),
+		filepath.Join(root, "b.go"),
+		filepath.Join(root, "sub", "new.go"),
+	}, changed)
+
+	staged, err := gitSelection(root, &ClipOptions{GitStaged: true})
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(root, "b.go")}, staged)
+
+	since, err := gitSelection(root, &ClipOptions{GitSince: "v1"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		filepath.Join(root, "a.go"),
+		filepath.Join(root, "b.go"),
+		filepath.Join(root, "sub", "new.go"),
+	}, since, "uncommitted changes, including untracked files")
+
+	sub, err := gitSelection(filepath.Join(root, "sub"), &ClipOptions{GitChanged: true})
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(root, "sub", "new.go")}, sub, "only files under the root")
+}
+
+func Test_gitSelection_Since_Merge_Base(t *testing.T) {
+	root, cleanup := testGitRepo(t)
+	defer cleanup()
+
+	git := func(args ...string) {
+		_, err := runGit(root, args...)
+		require.NoError(t, err, "git %v", args)
+	}
+	commit := func(name string) {
+		require.NoError(t, os.WriteFile(filepath.Join(root, name), []byte("package a\n\n// "+name+"\n"), 0644))
+		git("add", name)
+		git("commit", "-q", "-m", name)
+	}
+
+	git("branch", "base")
+	git("checkout", "-q", "-b", "feature")
+	commit("a.go")
+	git("checkout", "-q", "base")
+	commit("b.go") // Only on the base, after the branch forked
+	git("checkout", "-q", "feature")
+	require.NoError(t, os.WriteFile(filepath.Join(root, "c.go"), []byte("package a // uncommitted\n"), 0644))
+
+	since, err := gitSelection(root, &ClipOptions{GitSince: "base"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(root, "a.go"), filepath.Join(root, "c.go")}, since)
+}
+
+func Test_gitSelection_Fail_Unknown_Ref(t *testing.T) {
+	root, cleanup := testGitRepo(t)
+	defer cleanup()
+
+	_, err := gitSelection(root, &ClipOptions{GitSince: "does-not-exist"})
+	require.Error(t, err)
+
+	out := filepath.Join(root, "out.txt")
+	_, err = gitSelection(root, &ClipOptions{GitSince: "--output=" + out})
+	require.Error(t, err, "an option is not a ref")
+	assert.NoFileExists(t, out+"...HEAD")
+}
+
+func Test_selectGitFiles_Applies_Filter(t *testing.T) {
+	root, cleanup := testGitRepo(t)
+	defer cleanup()
+
+	for _, name := range []string{"a.go", "notes.txt"} {
+		err := os.WriteFile(filepath.Join(root, name), []byte("changed\n"), 0644)
+		require.NoError(t, err)
+	}
+
+	opts := &ClipOptions{Paths: []string{root}, GitChanged: true, Include: []string{"**/*.go"}}
+	filter, err := newFileFilter(root, opts)
+	require.NoError(t, err)
+
+	files, err := selectGitFiles(context.Background(), root, opts, filter)
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(root, "a.go")}, files)
+}
+
+func TestClipOptions_Validate_Git_Modes(t *testing.T) {
+	err := (&ClipOptions{Paths: []string{"."}, GitChanged: true, GitSince: "main"}).Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "only one of")
+
+	assert.NoError(t, (&ClipOptions{Paths: []string{"."}, GitStaged: true}).Validate())
+
+	err = (&ClipOptions{Paths: []string{"."}, GitSince: "--output=x"}).Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not an option")
+}
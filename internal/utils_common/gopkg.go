@@ -0,0 +1,234 @@
+package utils_common
+
+import (
+	"context"
+	"fmt"
+	"go/parser"
+	"go/token"
+	"golang.org/x/mod/modfile"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// goModule is the module a Go package belongs to.
+type goModule struct {
+	Root string // Directory holding the go.mod file
+	Path string // Module path declared in the go.mod file
+}
+
+// goPackage is a package of a module, read from its directory.
+type goPackage struct {
+	Dir     string
+	Files   []string // Absolute paths of the package's Go files, sorted
+	Imports []string // Directories of the packages imported from the same module, sorted
+}
+
+// findGoModule returns the module of the directory, found by looking
+// for a go.mod file in it and in every parent.
+func findGoModule(dir string) (*goModule, error) {
+	for current := dir; ; {
+		data, err := os.ReadFile(filepath.Join(current, "go.mod"))
+		if err == nil {
+			modulePath := modfile.ModulePath(data)
+			if modulePath == "" {
+				return nil, fmt.Errorf("no module path in '%s'", filepath.Join(current, "go.mod"))
+			}
+			return &goModule{Root: current, Path: modulePath}, nil
+		}
+		if !os.IsNotExist(err) {
+			return nil, err
+		}
+
+		parent := filepath.Dir(current)
+		if parent == current {
+			return nil, fmt.Errorf("no go.mod found above '%s'", dir)
+		}
+		current = parent
+	}
+}
+
+// importDir returns the directory of an import path of the module,
+// or false when the import belongs to the standard library or another module.
+func (m *goModule) importDir(importPath string) (string, bool) {
+	if importPath == m.Path {
+		return m.Root, true
+	}
+	rest, ok := strings.CutPrefix(importPath, m.Path+"/")
+	if !ok {
+		return "", false
+	}
+	return filepath.Join(m.Root, filepath.FromSlash(rest)), true
+}
+
+// packageDir resolves a package given as a directory (e.g "./internal/models"),
+// or as an import path of the working directory's module.
+func packageDir(pkg string) (string, error) {
+	abs, err := filepath.Abs(pkg)
+	if err != nil {
+		return "", err
+	}
+	if info, err := os.Stat(abs); err == nil && info.IsDir() {
+		return abs, nil
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+	module, err := findGoModule(wd)
+	if err == nil {
+		if dir, ok := module.importDir(path.Clean(pkg)); ok {
+			if info, err := os.Stat(dir); err == nil && info.IsDir() {
+				return dir, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("package '%s' is neither a directory, nor a package of the module", pkg)
+}
+
+// loadGoPackage lists the Go files of the directory and the packages of the
+// module that they import, using only the import clauses. Files that the go
+// tool ignores (starting with "_" or ".") are left out and so are tests
+// when noTests is set.
+func loadGoPackage(dir string, module *goModule, noTests bool) (*goPackage, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	pkg := goPackage{Dir: dir}
+	imports := make(map[string]bool)
+	fset := token.NewFileSet()
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || filepath.Ext(name) != ".go" || strings.HasPrefix(name, "_") || strings.HasPrefix(name, ".") {
+			continue
+		}
+		if noTests && strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+
+		file := filepath.Join(dir, name)
+		parsed, err := parser.ParseFile(fset, file, nil, parser.ImportsOnly)
+		if err != nil {
+			return nil, fmt.Errorf("parse imports: %v", err)
+		}
+		pkg.Files = append(pkg.Files, file)
+
+		for _, spec := range parsed.Imports {
+			importPath, err := strconv.Unquote(spec.Path.Value)
+			if err != nil {
+				return nil, fmt.Errorf("import path %s of '%s': %v", spec.Path.Value, file, err)
+			}
+			if importDir, ok := module.importDir(importPath); ok && importDir != dir {
+				imports[importDir] = true
+			}
+		}
+	}
+
+	if len(pkg.Files) == 0 {
+		return nil, fmt.Errorf("no Go files in '%s'", dir)
+	}
+
+	for importDir := range imports {
+		pkg.Imports = append(pkg.Imports, importDir)
+	}
+	sort.Strings(pkg.Files)
+	sort.Strings(pkg.Imports)
+
+	return &pkg, nil
+}
+
+// resolveGoPackages returns the Go files of the packages of the options, in
+// dependency order (the dependencies of a package come before it). With Deps,
+// the packages of the same module they import are added transitively, up to
+// DepsDepth levels of imports (zero being no limit). Only the import clauses
+// are parsed, so nothing is built or downloaded.
+func resolveGoPackages(ctx context.Context, opts *ClipOptions) ([]string, error) {
+	loaded := make(map[string]*goPackage)
+	load := func(dir string, module *goModule) (*goPackage, error) {
+		if pkg, ok := loaded[dir]; ok {
+			return pkg, nil
+		}
+		pkg, err := loadGoPackage(dir, module, opts.NoTests)
+		if err != nil {
+			return nil, err
+		}
+		loaded[dir] = pkg
+		return pkg, nil
+	}
+
+	roots := make([]string, 0, len(opts.GoPackages))
+	modules := make(map[string]*goModule)
+	depths := make(map[string]int)
+	var queue []string
+
+	for _, name := range opts.GoPackages {
+		dir, err := packageDir(name)
+		if err != nil {
+			return nil, err
+		}
+		module, err := findGoModule(dir)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := depths[dir]; !ok {
+			roots = append(roots, dir)
+			modules[dir] = module
+			depths[dir] = 0
+			queue = append(queue, dir)
+		}
+	}
+
+	// Breadth first, so every package is reached at its shallowest depth.
+	for len(queue) > 0 {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		dir := queue[0]
+		queue = queue[1:]
+
+		pkg, err := load(dir, modules[dir])
+		if err != nil {
+			return nil, err
+		}
+		if !opts.Deps || (opts.DepsDepth > 0 && depths[dir] >= opts.DepsDepth) {
+			continue
+		}
+		for _, importDir := range pkg.Imports {
+			if _, ok := depths[importDir]; ok {
+				continue
+			}
+			depths[importDir] = depths[dir] + 1
+			modules[importDir] = modules[dir]
+			queue = append(queue, importDir)
+		}
+	}
+
+	var files []string
+	done := make(map[string]bool)
+	var visit func(dir string)
+	visit = func(dir string) {
+		if done[dir] {
+			return
+		}
+		done[dir] = true // Set before the imports, as test files may import back
+		for _, importDir := range loaded[dir].Imports {
+			if _, ok := loaded[importDir]; ok {
+				visit(importDir)
+			}
+		}
+		files = append(files, loaded[dir].Files...)
+	}
+	for _, root := range roots {
+		visit(root)
+	}
+
+	return files, nil
+}
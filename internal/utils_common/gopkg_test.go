@@ -0,0 +1,114 @@
+package utils_common
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// testCreateGoModule writes a module "example.com/m" where "app" imports
+// "store", which imports "models" and the tests of "app" import "fixtures".
+func testCreateGoModule(t *testing.T) string {
+	root := t.TempDir()
+	files := map[string]string{
+		"go.mod":                   "module example.com/m\n\ngo 1.21\n",
+		"app/app.go":               "package app\n\nimport (\n\t\"fmt\"\n\t\"example.com/m/store\"\n\t\"github.com/other/lib\"\n)\n",
+		"app/app_test.go":          "package app\n\nimport \"example.com/m/fixtures\"\n",
+		"app/_scratch.go":          "package app\n",
+		"store/store.go":           "package store\n\nimport \"example.com/m/models\"\n",
+		"models/models.go":         "package models\n",
+		"models/sub/unrelated.go":  "package sub\n",
+		"fixtures/fixtures.go":     "package fixtures\n\nimport \"example.com/m/models\"\n",
+		"fixtures/testdata/x.json": "{}\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+	return root
+}
+
+func Test_resolveGoPackages(t *testing.T) {
+	root := testCreateGoModule(t)
+
+	testCases := []struct {
+		name     string
+		opts     ClipOptions
+		expected []string
+	}{
+		{
+			name:     "Package_Only",
+			opts:     ClipOptions{},
+			expected: []string{"app/app.go", "app/app_test.go"},
+		},
+		{
+			name:     "Deps",
+			opts:     ClipOptions{Deps: true},
+			expected: []string{"models/models.go", "fixtures/fixtures.go", "store/store.go", "app/app.go", "app/app_test.go"},
+		},
+		{
+			name:     "Deps_Depth",
+			opts:     ClipOptions{Deps: true, DepsDepth: 1},
+			expected: []string{"fixtures/fixtures.go", "store/store.go", "app/app.go", "app/app_test.go"},
+		},
+		{
+			name:     "No_Tests",
+			opts:     ClipOptions{Deps: true, NoTests: true},
+			expected: []string{"models/models.go", "store/store.go", "app/app.go"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.opts.GoPackages = []string{filepath.Join(root, "app")}
+
+			files, err := resolveGoPackages(context.Background(), &tc.opts)
+			require.NoError(t, err)
+
+			rel := make([]string, 0, len(files))
+			for _, file := range files {
+				r, err := filepath.Rel(root, file)
+				require.NoError(t, err)
+				rel = append(rel, filepath.ToSlash(r))
+			}
+			assert.Equal(t, tc.expected, rel)
+		})
+	}
+}
+
+func Test_resolveGoPackages_Fail_No_Go_Files(t *testing.T) {
+	root := testCreateGoModule(t)
+
+	_, err := resolveGoPackages(context.Background(), &ClipOptions{GoPackages: []string{filepath.Join(root, "fixtures", "testdata")}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no Go files")
+}
+
+func Test_selectFiles_Go_Packages_Keep_Dependency_Order(t *testing.T) {
+	root := testCreateGoModule(t)
+
+	base, files, _, err := selectFiles(context.Background(), &ClipOptions{
+		GoPackages: []string{filepath.Join(root, "store")},
+		Deps:       true,
+		Exclude:    []string{"**/*_test.go"},
+	})
+	require.NoError(t, err)
+
+	var rel []string
+	for _, file := range files {
+		rel = append(rel, file.Rel)
+	}
+	assert.Equal(t, root, base)
+	assert.Equal(t, []string{"models/models.go", "store/store.go"}, rel)
+}
+
+func TestClipOptions_Validate_Go_Packages(t *testing.T) {
+	require.NoError(t, (&ClipOptions{GoPackages: []string{"./app"}, Deps: true}).Validate())
+	require.Error(t, (&ClipOptions{GoPackages: []string{"./app"}, Paths: []string{"."}}).Validate())
+	require.Error(t, (&ClipOptions{GoPackages: []string{"./app"}, GitStaged: true}).Validate())
+	require.Error(t, (&ClipOptions{Paths: []string{"."}, Deps: true}).Validate())
+}
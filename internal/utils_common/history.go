@@ -0,0 +1,278 @@
+package utils_common
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/atotto/clipboard"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	historyAppDir     = "overwatch"
+	historyEntryExt   = ".json"
+	historyContentExt = ".txt"
+)
+
+// ErrHistoryEntryNotFound is returned for an id that is not in the history.
+var ErrHistoryEntryNotFound = errors.New("history entry not found")
+
+// HistoryEntry is a single clipboard write, its content being stored aside.
+type HistoryEntry struct {
+	ID      int       `json:"id"`
+	Time    time.Time `json:"time"`
+	Command string    `json:"command"`
+	Args    []string  `json:"args"`
+	Size    int64     `json:"size"`
+	Hash    string    `json:"hash"`
+}
+
+// History is a local store of everything that was put onto the clipboard.
+//
+// Every entry is kept as two files in Dir: "<id>.json" holds the entry and
+// "<id>.txt" the content. Entries beyond MaxEntries or older than MaxAge are
+// pruned as new ones are recorded (zero meaning no limit), although the latest
+// entry is always kept.
+type History struct {
+	Dir        string
+	MaxEntries int
+	MaxAge     time.Duration
+
+	mu  sync.Mutex
+	now func() time.Time
+}
+
+func NewHistory(dir string, maxEntries int, maxAge time.Duration) *History {
+	return &History{
+		Dir:        dir,
+		MaxEntries: maxEntries,
+		MaxAge:     maxAge,
+		now:        time.Now,
+	}
+}
+
+// DefaultHistoryDir is the history's directory under the user's data
+// directory, e.g "~/.local/share/overwatch/history" on Linux.
+func DefaultHistoryDir() (string, error) {
+	if dir := os.Getenv("XDG_DATA_HOME"); dir != "" {
+		return filepath.Join(dir, historyAppDir, "history"), nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("home dir: %v", err)
+	}
+
+	var dataDir string
+	switch runtime.GOOS {
+	case "darwin":
+		dataDir = filepath.Join(home, "Library", "Application Support")
+	case "windows":
+		dataDir = os.Getenv("LocalAppData")
+		if dataDir == "" {
+			dataDir = filepath.Join(home, "AppData", "Local")
+		}
+	default:
+		dataDir = filepath.Join(home, ".local", "share")
+	}
+
+	return filepath.Join(dataDir, historyAppDir, "history"), nil
+}
+
+// Record stores the content as the newest entry and prunes the
+// entries that fall out of the retention policy.
+func (h *History) Record(command string, args []string, content string) (*HistoryEntry, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if err := os.MkdirAll(h.Dir, 0700); err != nil {
+		return nil, fmt.Errorf("mkdir: %v", err)
+	}
+
+	entries, err := h.list()
+	if err != nil {
+		return nil, err
+	}
+
+	id := 1
+	if len(entries) > 0 {
+		id = entries[0].ID + 1
+	}
+
+	hash := sha256.Sum256([]byte(content))
+	entry := HistoryEntry{
+		ID:      id,
+		Time:    h.now().UTC(),
+		Command: command,
+		Args:    args,
+		Size:    int64(len(content)),
+		Hash:    hex.EncodeToString(hash[:]),
+	}
+
+	data, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("marshal: %v", err)
+	}
+	if err := os.WriteFile(h.path(id, historyContentExt), []byte(content), 0600); err != nil {
+		return nil, fmt.Errorf("write content: %v", err)
+	}
+	if err := os.WriteFile(h.path(id, historyEntryExt), data, 0600); err != nil {
+		return nil, fmt.Errorf("write entry: %v", err)
+	}
+
+	if err := h.prune(append([]HistoryEntry{entry}, entries...)); err != nil {
+		return nil, fmt.Errorf("prune: %v", err)
+	}
+
+	return &entry, nil
+}
+
+// List returns the entries, newest first.
+func (h *History) List() ([]HistoryEntry, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return h.list()
+}
+
+// Get returns the entry and its content.
+func (h *History) Get(id int) (*HistoryEntry, string, error) {
+	entry, err := h.entry(id)
+	if err != nil {
+		return nil, "", err
+	}
+
+	content, err := os.ReadFile(h.path(id, historyContentExt))
+	if err != nil {
+		return nil, "", fmt.Errorf("read content: %v", err)
+	}
+
+	return entry, string(content), nil
+}
+
+// entry reads the entry alone, leaving its content on disk.
+func (h *History) entry(id int) (*HistoryEntry, error) {
+	data, err := os.ReadFile(h.path(id, historyEntryExt))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%w: %d", ErrHistoryEntryNotFound, id)
+		}
+		return nil, fmt.Errorf("read entry: %v", err)
+	}
+
+	var entry HistoryEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, fmt.Errorf("unmarshal entry %d: %v", id, err)
+	}
+
+	return &entry, nil
+}
+
+// Restore puts the entry's content back onto the clipboard.
+// Restoring is not recorded as a new entry.
+func (h *History) Restore(id int) (*HistoryEntry, error) {
+	entry, content, err := h.Get(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := clipboardWrite(content); err != nil {
+		return nil, fmt.Errorf("clip: %v", err)
+	}
+
+	return entry, nil
+}
+
+// list reads the entries, newest first, without their contents.
+func (h *History) list() ([]HistoryEntry, error) {
+	matches, err := filepath.Glob(filepath.Join(h.Dir, "*"+historyEntryExt))
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]HistoryEntry, 0, len(matches))
+	for _, match := range matches {
+		id, err := strconv.Atoi(strings.TrimSuffix(filepath.Base(match), historyEntryExt))
+		if err != nil {
+			continue // Not an entry
+		}
+		entry, err := h.entry(id)
+		if err != nil {
+			logger.Warnf("skipping history entry %d: %v", id, err)
+			continue
+		}
+		entries = append(entries, *entry)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].ID > entries[j].ID
+	})
+
+	return entries, nil
+}
+
+// prune removes the entries (newest first) beyond MaxEntries
+// or older than MaxAge, except for the newest one.
+func (h *History) prune(entries []HistoryEntry) error {
+	now := h.now()
+	for i, entry := range entries {
+		if i == 0 {
+			continue
+		}
+		tooMany := h.MaxEntries > 0 && i >= h.MaxEntries
+		tooOld := h.MaxAge > 0 && now.Sub(entry.Time) > h.MaxAge
+		if !tooMany && !tooOld {
+			continue
+		}
+		for _, ext := range []string{historyEntryExt, historyContentExt} {
+			if err := os.Remove(h.path(entry.ID, ext)); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (h *History) path(id int, ext string) string {
+	return filepath.Join(h.Dir, fmt.Sprintf("%06d%s", id, ext))
+}
+
+// clipboardWrite puts text onto the system clipboard.
+var clipboardWrite = clipboard.WriteAll
+
+// CopyToClipboard puts the text onto the clipboard and records it in the
+// clip history, when given, along with the command line that produced it.
+// A failure to record is only logged, as the clipboard write itself succeeded.
+func CopyToClipboard(text string, history *History) error {
+	if err := clipboardWrite(text); err != nil {
+		return err
+	}
+
+	if history == nil {
+		return nil
+	}
+
+	command, args := invocation(os.Args)
+	if _, err := history.Record(command, args, text); err != nil {
+		logger.Warnf("recording the clip into the history failed: %v", err)
+	}
+
+	return nil
+}
+
+// invocation splits the command line into the command and its arguments.
+func invocation(osArgs []string) (string, []string) {
+	if len(osArgs) < 2 {
+		return "", []string{}
+	}
+	return osArgs[1], append([]string{}, osArgs[2:]...)
+}
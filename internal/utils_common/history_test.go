@@ -0,0 +1,132 @@
+package utils_common
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func testHistory(t *testing.T, maxEntries int, maxAge time.Duration) (*History, *time.Time) {
+	t.Helper()
+
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	history := NewHistory(filepath.Join(t.TempDir(), "history"), maxEntries, maxAge)
+	history.now = func() time.Time { return now }
+
+	return history, &now
+}
+
+func TestHistory_Record_And_Get(t *testing.T) {
+	history, _ := testHistory(t, 0, 0)
+
+	first, err := history.Record("clip-file-contents", []string{"internal", "--stdout"}, "hello")
+	require.NoError(t, err, "record")
+	second, err := history.Record("clip-gpt-preface", []string{}, "world")
+	require.NoError(t, err, "record")
+
+	assert.Equal(t, 1, first.ID)
+	assert.Equal(t, 2, second.ID)
+	assert.Equal(t, int64(5), first.Size)
+	assert.Equal(t, "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824", first.Hash)
+
+	entry, content, err := history.Get(1)
+	require.NoError(t, err, "get")
+	assert.Equal(t, *first, *entry)
+	assert.Equal(t, "hello", content)
+
+	entries, err := history.List()
+	require.NoError(t, err, "list")
+	require.Len(t, entries, 2)
+	assert.Equal(t, 2, entries[0].ID, "newest first")
+}
+
+func TestHistory_List_Reads_No_Contents(t *testing.T) {
+	history, _ := testHistory(t, 0, 0)
+
+	_, err := history.Record("cmd", nil, "content")
+	require.NoError(t, err, "record")
+	require.NoError(t, os.Remove(history.path(1, historyContentExt)))
+
+	entries, err := history.List()
+	require.NoError(t, err, "list")
+	require.Len(t, entries, 1, "listing reads the entries alone")
+
+	_, _, err = history.Get(1)
+	require.Error(t, err, "getting reads the content")
+}
+
+func TestHistory_Get_Fail_Missing(t *testing.T) {
+	history, _ := testHistory(t, 0, 0)
+
+	_, _, err := history.Get(42)
+	require.ErrorIs(t, err, ErrHistoryEntryNotFound)
+}
+
+func TestHistory_Retention_By_Count(t *testing.T) {
+	history, _ := testHistory(t, 2, 0)
+
+	for _, content := range []string{"a", "b", "c"} {
+		_, err := history.Record("cmd", nil, content)
+		require.NoError(t, err, "record")
+	}
+
+	entries, err := history.List()
+	require.NoError(t, err, "list")
+	require.Len(t, entries, 2)
+	assert.Equal(t, 3, entries[0].ID)
+	assert.Equal(t, 2, entries[1].ID)
+}
+
+func TestHistory_Retention_By_Age(t *testing.T) {
+	history, now := testHistory(t, 0, time.Hour)
+
+	_, err := history.Record("cmd", nil, "old")
+	require.NoError(t, err, "record")
+
+	*now = now.Add(2 * time.Hour)
+	_, err = history.Record("cmd", nil, "new")
+	require.NoError(t, err, "record")
+
+	entries, err := history.List()
+	require.NoError(t, err, "list")
+	require.Len(t, entries, 1)
+	assert.Equal(t, 2, entries[0].ID, "ids keep increasing")
+}
+
+func TestCopyToClipboard_Records_History(t *testing.T) {
+	history, _ := testHistory(t, 0, 0)
+
+	var clipped string
+	defer func(write func(string) error) { clipboardWrite = write }(clipboardWrite)
+	clipboardWrite = func(text string) error {
+		clipped = text
+		return nil
+	}
+
+	require.NoError(t, CopyToClipboard("content", history), "copy")
+
+	entries, err := history.List()
+	require.NoError(t, err, "list")
+	require.Len(t, entries, 1)
+	assert.Equal(t, "content", clipped)
+
+	_, err = history.Restore(entries[0].ID)
+	require.NoError(t, err, "restore")
+
+	entries, err = history.List()
+	require.NoError(t, err, "list")
+	assert.Len(t, entries, 1, "restoring is not recorded")
+}
+
+func Test_invocation(t *testing.T) {
+	command, args := invocation([]string{"cli", "clip-file-contents", "internal", "--stdout"})
+	assert.Equal(t, "clip-file-contents", command)
+	assert.Equal(t, []string{"internal", "--stdout"}, args)
+
+	command, args = invocation([]string{"cli"})
+	assert.Equal(t, "", command)
+	assert.Empty(t, args)
+}
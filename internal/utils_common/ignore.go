@@ -0,0 +1,266 @@
+package utils_common
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+const (
+	gitIgnoreFile       = ".gitignore"
+	overwatchIgnoreFile = ".overwatchignore"
+)
+
+// gitInfoExclude is the repository-local exclude file, relative
+// to the directory holding the ".git" folder.
+var gitInfoExclude = filepath.Join(".git", "info", "exclude")
+
+// ignoreRule is a single compiled line of an ignore file.
+type ignoreRule struct {
+	base    string
+	negate  bool
+	dirOnly bool
+	re      *regexp.Regexp
+}
+
+// ignoreMatcher evaluates gitignore style rules that were collected
+// from ignore files across the directories of a walk.
+//
+// Rules are kept per directory and are evaluated from the outermost
+// directory to the innermost, so the last matching rule wins, just like git.
+type ignoreMatcher struct {
+	rules  map[string][]ignoreRule
+	loaded map[string]bool
+}
+
+func newIgnoreMatcher() *ignoreMatcher {
+	return &ignoreMatcher{
+		rules:  make(map[string][]ignoreRule),
+		loaded: make(map[string]bool),
+	}
+}
+
+// newIgnoreMatcherForRoot creates a matcher pre-loaded with the ignore
+// files of every directory between the enclosing git repository and the root.
+func newIgnoreMatcherForRoot(root string) (*ignoreMatcher, error) {
+	m := newIgnoreMatcher()
+
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+
+	// Ignore files above the root only apply when they belong
+	// to the same repository, so we stop at the repository's top.
+	var ancestors []string
+	if !isDir(filepath.Join(absRoot, ".git")) {
+		for dir := filepath.Dir(absRoot); ; dir = filepath.Dir(dir) {
+			ancestors = append([]string{dir}, ancestors...)
+			if isDir(filepath.Join(dir, ".git")) {
+				break
+			}
+			if filepath.Dir(dir) == dir {
+				ancestors = nil // Not inside a repository
+				break
+			}
+		}
+	}
+
+	for _, dir := range ancestors {
+		if err := m.load(dir); err != nil {
+			return nil, err
+		}
+	}
+
+	return m, nil
+}
+
+// load reads the ignore files found in dir, once.
+func (m *ignoreMatcher) load(dir string) error {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
+	if m.loaded[dir] {
+		return nil
+	}
+	m.loaded[dir] = true
+
+	// Order matters, from the lowest to the highest precedence.
+	for _, name := range []string{gitInfoExclude, gitIgnoreFile, overwatchIgnoreFile} {
+		rules, err := readIgnoreFile(filepath.Join(dir, name), dir)
+		if err != nil {
+			return err
+		}
+		m.rules[dir] = append(m.rules[dir], rules...)
+	}
+
+	return nil
+}
+
+// ignored returns true if the path is excluded by the loaded rules.
+func (m *ignoreMatcher) ignored(path string, isDir bool) bool {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return false
+	}
+
+	var dirs []string
+	for dir := filepath.Dir(abs); ; dir = filepath.Dir(dir) {
+		dirs = append([]string{dir}, dirs...)
+		if filepath.Dir(dir) == dir {
+			break
+		}
+	}
+
+	ignored := false
+	for _, dir := range dirs {
+		for _, rule := range m.rules[dir] {
+			if rule.matches(abs, isDir) {
+				ignored = !rule.negate
+			}
+		}
+	}
+
+	return ignored
+}
+
+func (r ignoreRule) matches(abs string, isDir bool) bool {
+	if r.dirOnly && !isDir {
+		return false
+	}
+	rel, err := filepath.Rel(r.base, abs)
+	if err != nil {
+		return false
+	}
+	return r.re.MatchString(filepath.ToSlash(rel))
+}
+
+func readIgnoreFile(path string, base string) ([]ignoreRule, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var rules []ignoreRule
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		rule, ok := parseIgnoreLine(scanner.Text(), base)
+		if ok {
+			rules = append(rules, rule)
+		}
+	}
+
+	return rules, scanner.Err()
+}
+
+// parseIgnoreLine compiles a single gitignore line.
+// It returns false for blank lines and comments.
+func parseIgnoreLine(line string, base string) (ignoreRule, bool) {
+	rule := ignoreRule{base: base}
+
+	line = strings.TrimSuffix(line, "\r")
+	line = trimUnescapedTrailingSpaces(line)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return rule, false
+	}
+
+	if strings.HasPrefix(line, "!") {
+		rule.negate = true
+		line = line[1:]
+	}
+
+	if strings.HasSuffix(line, "/") {
+		rule.dirOnly = true
+		line = strings.TrimRight(line, "/")
+	}
+
+	if line == "" {
+		return rule, false
+	}
+
+	// A separator at the beginning or in the middle anchors the
+	// pattern to the ignore file's directory.
+	anchored := strings.Contains(line, "/")
+	line = strings.TrimPrefix(line, "/")
+	if !anchored {
+		line = "**/" + line
+	}
+
+	re, err := regexp.Compile("^" + ignorePatternToRegex(line) + "$")
+	if err != nil {
+		return rule, false
+	}
+	rule.re = re
+
+	return rule, true
+}
+
+func trimUnescapedTrailingSpaces(line string) string {
+	for strings.HasSuffix(line, " ") && !strings.HasSuffix(line, `\ `) {
+		line = line[:len(line)-1]
+	}
+	return line
+}
+
+// ignorePatternToRegex converts a gitignore glob into a regular expression.
+func ignorePatternToRegex(pattern string) string {
+	var sb strings.Builder
+
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		switch c {
+		case '*':
+			if strings.HasPrefix(pattern[i:], "**") {
+				atStart := i == 0 || pattern[i-1] == '/'
+				atEnd := i+2 == len(pattern)
+				nextIsSep := i+2 < len(pattern) && pattern[i+2] == '/'
+				switch {
+				case atStart && nextIsSep:
+					sb.WriteString("(?:.*/)?")
+					i += 2
+					continue
+				case atStart && atEnd:
+					sb.WriteString(".*")
+					i++
+					continue
+				}
+			}
+			sb.WriteString("[^/]*")
+		case '?':
+			sb.WriteString("[^/]")
+		case '[':
+			end := strings.IndexByte(pattern[i+1:], ']')
+			if end < 0 {
+				sb.WriteString(`\[`)
+				continue
+			}
+			class := pattern[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			sb.WriteString("[" + strings.ReplaceAll(class, `\`, `\\`) + "]")
+			i += end + 1
+		case '\\':
+			if i+1 < len(pattern) {
+				i++
+				sb.WriteString(regexp.QuoteMeta(string(pattern[i])))
+			}
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+
+	return sb.String()
+}
+
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
@@ -0,0 +1,94 @@
+package utils_common
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func Test_parseIgnoreLine(t *testing.T) {
+	type testCase struct {
+		name    string
+		pattern string
+		path    string
+		isDir   bool
+		matches bool
+	}
+
+	testCases := []testCase{
+		{name: "Name At Any Depth", pattern: "node_modules", path: "web/node_modules", isDir: true, matches: true},
+		{name: "Name At Root", pattern: "node_modules", path: "node_modules", isDir: true, matches: true},
+		{name: "Anchored Pattern", pattern: "/build", path: "build", isDir: true, matches: true},
+		{name: "Anchored Pattern Nested", pattern: "/build", path: "cmd/build", isDir: true, matches: false},
+		{name: "Middle Separator Anchors", pattern: "docs/*.md", path: "docs/a.md", matches: true},
+		{name: "Middle Separator Nested", pattern: "docs/*.md", path: "x/docs/a.md", matches: false},
+		{name: "Star Does Not Cross Separator", pattern: "docs/*.md", path: "docs/x/a.md", matches: false},
+		{name: "Leading Double Star", pattern: "**/fakes", path: "a/b/fakes", isDir: true, matches: true},
+		{name: "Trailing Double Star", pattern: "vendor/**", path: "vendor/a/b.go", matches: true},
+		{name: "Middle Double Star", pattern: "a/**/b", path: "a/x/y/b", matches: true},
+		{name: "Middle Double Star Zero Dirs", pattern: "a/**/b", path: "a/b", matches: true},
+		{name: "Dir Only Rule On Dir", pattern: "out/", path: "out", isDir: true, matches: true},
+		{name: "Dir Only Rule On File", pattern: "out/", path: "out", isDir: false, matches: false},
+		{name: "Extension", pattern: "*.env", path: "config/prod.env", matches: true},
+		{name: "Question Mark", pattern: "file?.txt", path: "file1.txt", matches: true},
+		{name: "Character Class", pattern: "file[0-9].txt", path: "fileA.txt", matches: false},
+		{name: "Negated Character Class", pattern: "file[!0-9].txt", path: "fileA.txt", matches: true},
+		{name: "Escaped Hash", pattern: `\#notes`, path: "#notes", matches: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rule, ok := parseIgnoreLine(tc.pattern, "/base")
+			require.True(t, ok, "pattern should compile")
+			assert.Equal(t, tc.matches, rule.matches(filepath.Join("/base", tc.path), tc.isDir))
+		})
+	}
+}
+
+func Test_parseIgnoreLine_Skips_Comments_And_Blanks(t *testing.T) {
+	for _, line := range []string{"", "   ", "# comment", "!"} {
+		_, ok := parseIgnoreLine(line, "/base")
+		assert.False(t, ok, "line %q should be skipped", line)
+	}
+}
+
+func Test_visit_Honors_Ignore_Files(t *testing.T) {
+	arrTestDir, cleanup := testCreateTestDir(t)
+	defer cleanup()
+
+	root := filepath.Join(arrTestDir...)
+
+	testCreateFilesAndFolders(t, arrTestDir, []fileDetail{
+		{Name: "node_modules", Type: fileTypeFolder},
+		{Name: filepath.Join("node_modules", "lib.js"), Type: fileTypeFile},
+		{Name: "logs", Type: fileTypeFolder},
+		{Name: filepath.Join("logs", "a.log"), Type: fileTypeFile},
+		{Name: filepath.Join("logs", "keep.log"), Type: fileTypeFile},
+		{Name: "sub", Type: fileTypeFolder},
+		{Name: filepath.Join("sub", "secret.txt"), Type: fileTypeFile},
+		{Name: filepath.Join("sub", "notes.txt"), Type: fileTypeFile},
+		{Name: ".env", Type: fileTypeFile},
+		{Name: "main.go", Type: fileTypeFile},
+	})
+
+	writeFile := func(name, content string) {
+		err := os.WriteFile(filepath.Join(root, name), []byte(content), 0644)
+		require.NoError(t, err, "write %s", name)
+	}
+	writeFile(gitIgnoreFile, "node_modules/\n*.log\n!keep.log\n")
+	writeFile(overwatchIgnoreFile, ".env\n")
+	writeFile(filepath.Join("sub", gitIgnoreFile), "/secret.txt\n")
+
+	relFiles := testWalkSelection(t, root, &ClipOptions{})
+
+	assert.ElementsMatch(t, []string{
+		".gitignore",
+		".overwatchignore",
+		"logs/keep.log",
+		"main.go",
+		"sub/.gitignore",
+		"sub/notes.txt",
+	}, relFiles)
+}
@@ -0,0 +1,51 @@
+package utils_common
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// lineNumberSeparator splits a line's number from its text.
+const lineNumberSeparator = " | "
+
+// numberLines prefixes every line of the content with its right-aligned,
+// 1-based number, e.g "  7 | func main() {". All the numbers of a file share
+// the same width, so every line matches `^ *(\d+) \| ?(.*)$`. Blank lines get
+// no trailing space.
+func numberLines(content string) string {
+	return numberLinesFrom(content, 1)
+}
+
+// numberLinesFrom numbers the lines like numberLines, the first line being
+// first, for snippets taken from the middle of a file.
+func numberLinesFrom(content string, first int) string {
+	if content == "" {
+		return content
+	}
+
+	trailingNewline := strings.HasSuffix(content, "\n")
+	lines := strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+	width := len(strconv.Itoa(first + len(lines) - 1))
+
+	var sb strings.Builder
+	sb.Grow(len(content) + len(lines)*(width+len(lineNumberSeparator)))
+
+	for i, line := range lines {
+		if i > 0 {
+			sb.WriteByte('\n')
+		}
+		prefix := fmt.Sprintf("%*d%s", width, first+i, lineNumberSeparator)
+		if line == "" {
+			prefix = strings.TrimRight(prefix, " ")
+		}
+		sb.WriteString(prefix)
+		sb.WriteString(line)
+	}
+
+	if trailingNewline {
+		sb.WriteByte('\n')
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,52 @@
+package utils_common
+
+import (
+	"github.com/stretchr/testify/assert"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func Test_numberLines(t *testing.T) {
+	type testCase struct {
+		name     string
+		content  string
+		expected string
+	}
+
+	testCases := []testCase{
+		{name: "Empty", content: "", expected: ""},
+		{name: "Single Line", content: "package main", expected: "1 | package main"},
+		{name: "Trailing Newline", content: "a\n\nb\n", expected: "1 | a\n2 |\n3 | b\n"},
+		{
+			name:     "Right Aligned",
+			content:  strings.Repeat("x\n", 9) + "y\n",
+			expected: " 1 | x\n 2 | x\n 3 | x\n 4 | x\n 5 | x\n 6 | x\n 7 | x\n 8 | x\n 9 | x\n10 | y\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, numberLines(tc.content))
+		})
+	}
+}
+
+func Test_numberLines_Parseable(t *testing.T) {
+	content := "func main() {\n\n\tfmt.Println(\"a | b\")\n}\n"
+	re := regexp.MustCompile(`^ *(\d+) \| ?(.*)$`)
+
+	var parsed []string
+	for _, line := range strings.Split(strings.TrimSuffix(numberLines(content), "\n"), "\n") {
+		groups := re.FindStringSubmatch(line)
+		if assert.NotNil(t, groups, line) {
+			parsed = append(parsed, groups[2])
+		}
+	}
+
+	assert.Equal(t, strings.Split(strings.TrimSuffix(content, "\n"), "\n"), parsed)
+}
+
+func Test_numberLinesFrom(t *testing.T) {
+	assert.Equal(t, " 9 | a\n10 |\n11 | b\n", numberLinesFrom("a\n\nb\n", 9))
+}
@@ -0,0 +1,343 @@
+package utils_common
+
+import (
+	"go/scanner"
+	"go/token"
+	"path/filepath"
+	"strings"
+)
+
+// MinifiedFile is a file whose comments and blank lines were stripped.
+type MinifiedFile struct {
+	Path  string `json:"path"`
+	Saved int64  `json:"saved"`
+}
+
+// commentSyntax describes the comments and string literals of a language,
+// so that comments can be stripped without ever touching a string.
+type commentSyntax struct {
+	lineComments []string
+	blockComment [2]string
+	// quotes start and end string literals.
+	quotes string
+	// rawQuotes are the quotes inside which backslashes escape nothing.
+	rawQuotes string
+	// multilineQuotes are the quotes whose literals may span lines,
+	// the others end at the end of the line when left unterminated.
+	multilineQuotes string
+	// hashAfterSpace only starts "#" comments at the start of a line
+	// or after whitespace (e.g "$#" is not a comment in a shell script).
+	hashAfterSpace bool
+	// keepShebang keeps a "#!" interpreter line.
+	keepShebang bool
+}
+
+var (
+	cStyleComments = commentSyntax{
+		lineComments:    []string{"//"},
+		blockComment:    [2]string{"/*", "*/"},
+		quotes:          "\"'`",
+		multilineQuotes: "`",
+	}
+	jsonComments = commentSyntax{
+		lineComments: []string{"//"},
+		blockComment: [2]string{"/*", "*/"},
+		quotes:       "\"",
+	}
+	sqlComments = commentSyntax{
+		lineComments:    []string{"--"},
+		blockComment:    [2]string{"/*", "*/"},
+		quotes:          "'\"`",
+		multilineQuotes: "'\"`",
+	}
+	shellComments = commentSyntax{
+		lineComments:    []string{"#"},
+		quotes:          "'\"",
+		rawQuotes:       "'",
+		multilineQuotes: "'\"",
+		hashAfterSpace:  true,
+		keepShebang:     true,
+	}
+	yamlComments = commentSyntax{
+		lineComments:    []string{"#"},
+		quotes:          "'\"",
+		rawQuotes:       "'",
+		multilineQuotes: "'\"",
+		hashAfterSpace:  true,
+	}
+)
+
+// minifySyntaxes maps the extensions of the supported languages,
+// Go aside, to their comment syntax.
+var minifySyntaxes = map[string]commentSyntax{
+	".js":   cStyleComments,
+	".jsx":  cStyleComments,
+	".mjs":  cStyleComments,
+	".cjs":  cStyleComments,
+	".ts":   cStyleComments,
+	".tsx":  cStyleComments,
+	".json": jsonComments,
+	".sql":  sqlComments,
+	".sh":   shellComments,
+	".bash": shellComments,
+	".zsh":  shellComments,
+	".yml":  yamlComments,
+	".yaml": yamlComments,
+}
+
+// strippedSource is source whose comments were removed, with the line
+// breaks kept, so that its lines still match the original ones.
+type strippedSource struct {
+	text string
+	// commentLines are the (0-based) lines a comment was removed from.
+	commentLines map[int]bool
+	// stringLines are the lines a string literal spans across.
+	stringLines map[int]bool
+}
+
+// minifyContent strips the comments of the file's contents by the rules of
+// its language, drops the lines left empty and collapses runs of blank lines.
+// String literals are never touched. It returns false for unsupported
+// languages and for Go sources that do not scan.
+func minifyContent(path string, content string) (string, bool) {
+	var (
+		stripped strippedSource
+		ok       bool
+	)
+
+	ext := strings.ToLower(filepath.Ext(path))
+	if ext == ".go" {
+		stripped, ok = stripGoComments(path, content)
+	} else if syntax, found := minifySyntaxes[ext]; found {
+		stripped, ok = stripComments(content, syntax), true
+	}
+	if !ok {
+		return content, false
+	}
+
+	return collapseLines(content, stripped), true
+}
+
+// stripGoComments removes the comments of Go source with go/scanner, except
+// for directives (e.g "//go:generate" or "//go:build").
+func stripGoComments(path string, src string) (strippedSource, bool) {
+	stripped := strippedSource{
+		commentLines: make(map[int]bool),
+		stringLines:  make(map[int]bool),
+	}
+
+	fset := token.NewFileSet()
+	file := fset.AddFile(path, fset.Base(), len(src))
+
+	failed := false
+	var s scanner.Scanner
+	s.Init(file, []byte(src), func(token.Position, string) { failed = true }, scanner.ScanComments)
+
+	var sb strings.Builder
+	last := 0
+	for {
+		pos, tok, lit := s.Scan()
+		if tok == token.EOF {
+			break
+		}
+
+		offset := file.Offset(pos)
+		line := file.Line(pos) - 1
+
+		switch {
+		case tok == token.STRING && strings.Contains(lit, "\n"):
+			for i := 0; i <= strings.Count(lit, "\n"); i++ {
+				stripped.stringLines[line+i] = true
+			}
+		case tok == token.COMMENT && !isGoDirective(lit):
+			end := commentEnd(src, offset)
+			newlines := strings.Count(src[offset:end], "\n")
+			sb.WriteString(src[last:offset])
+			sb.WriteString(strings.Repeat("\n", newlines))
+			last = end
+			for i := 0; i <= newlines; i++ {
+				stripped.commentLines[line+i] = true
+			}
+		}
+	}
+	if failed {
+		return stripped, false
+	}
+
+	sb.WriteString(src[last:])
+	stripped.text = sb.String()
+
+	return stripped, true
+}
+
+// commentEnd returns the offset right after the Go comment starting at offset.
+func commentEnd(src string, offset int) int {
+	if strings.HasPrefix(src[offset:], "/*") {
+		if end := strings.Index(src[offset+2:], "*/"); end >= 0 {
+			return offset + 2 + end + 2
+		}
+		return len(src)
+	}
+	if end := strings.IndexByte(src[offset:], '\n'); end >= 0 {
+		return offset + end
+	}
+	return len(src)
+}
+
+func isGoDirective(comment string) bool {
+	return strings.HasPrefix(comment, "//go:") ||
+		strings.HasPrefix(comment, "//line ") ||
+		strings.HasPrefix(comment, "// +build")
+}
+
+// stripComments removes the comments of src by the syntax, scanning
+// string literals so that comment markers inside them are left alone.
+func stripComments(src string, syntax commentSyntax) strippedSource {
+	stripped := strippedSource{
+		commentLines: make(map[int]bool),
+		stringLines:  make(map[int]bool),
+	}
+
+	var (
+		sb    strings.Builder
+		line  int
+		quote byte
+	)
+
+	for i := 0; i < len(src); {
+		c := src[i]
+
+		if quote != 0 {
+			switch {
+			case c == '\\' && !strings.ContainsRune(syntax.rawQuotes, rune(quote)) && i+1 < len(src):
+				if src[i+1] == '\n' {
+					stripped.stringLines[line] = true
+					stripped.stringLines[line+1] = true
+					line++
+				}
+				sb.WriteString(src[i : i+2])
+				i += 2
+				continue
+			case c == quote:
+				quote = 0
+			case c == '\n' && strings.ContainsRune(syntax.multilineQuotes, rune(quote)):
+				stripped.stringLines[line] = true
+				stripped.stringLines[line+1] = true
+				line++
+			case c == '\n':
+				quote = 0 // Unterminated, the literal ends with the line
+				line++
+			}
+			sb.WriteByte(c)
+			i++
+			continue
+		}
+
+		if start := syntax.blockComment[0]; start != "" && strings.HasPrefix(src[i:], start) {
+			end := strings.Index(src[i+len(start):], syntax.blockComment[1])
+			if end < 0 {
+				end = len(src)
+			} else {
+				end += i + len(start) + len(syntax.blockComment[1])
+			}
+			newlines := strings.Count(src[i:end], "\n")
+			for l := line; l <= line+newlines; l++ {
+				stripped.commentLines[l] = true
+			}
+			sb.WriteString(strings.Repeat("\n", newlines))
+			line += newlines
+			i = end
+			continue
+		}
+
+		if isLineComment(src, i, syntax) {
+			end := strings.IndexByte(src[i:], '\n')
+			if end < 0 {
+				end = len(src)
+			} else {
+				end += i
+			}
+			stripped.commentLines[line] = true
+			i = end
+			continue
+		}
+
+		if strings.IndexByte(syntax.quotes, c) >= 0 {
+			quote = c
+		}
+		if c == '\n' {
+			line++
+		}
+		sb.WriteByte(c)
+		i++
+	}
+
+	stripped.text = sb.String()
+	return stripped
+}
+
+// isLineComment tells whether a line comment starts at offset i of src.
+func isLineComment(src string, i int, syntax commentSyntax) bool {
+	for _, marker := range syntax.lineComments {
+		if !strings.HasPrefix(src[i:], marker) {
+			continue
+		}
+		if marker != "#" {
+			return true
+		}
+		if syntax.keepShebang && i == 0 && strings.HasPrefix(src, "#!") {
+			return false
+		}
+		if !syntax.hashAfterSpace || i == 0 || strings.IndexByte(" \t\n", src[i-1]) >= 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// collapseLines drops the lines of the stripped source that only held
+// comments, trims the space left before removed comments and collapses
+// runs of blank lines into one. Lines spanned by string literals are kept
+// as they are.
+func collapseLines(original string, stripped strippedSource) string {
+	lines := strings.Split(stripped.text, "\n")
+	out := make([]string, 0, len(lines))
+
+	blank := true // Drops the leading blank lines
+	for i, line := range lines {
+		if stripped.stringLines[i] {
+			out = append(out, line)
+			blank = false
+			continue
+		}
+
+		if stripped.commentLines[i] {
+			line = strings.TrimRight(line, " \t")
+			if strings.TrimSpace(line) == "" {
+				continue
+			}
+		}
+
+		if strings.TrimSpace(line) == "" {
+			if blank {
+				continue
+			}
+			blank = true
+			out = append(out, "")
+			continue
+		}
+
+		blank = false
+		out = append(out, line)
+	}
+
+	for len(out) > 0 && out[len(out)-1] == "" {
+		out = out[:len(out)-1]
+	}
+
+	result := strings.Join(out, "\n")
+	if strings.HasSuffix(original, "\n") && result != "" {
+		result += "\n"
+	}
+	return result
+}
@@ -0,0 +1,81 @@
+package utils_common
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func Test_minifyContent(t *testing.T) {
+	type testCase struct {
+		name     string
+		path     string
+		content  string
+		expected string
+	}
+
+	testCases := []testCase{
+		{
+			name: "Go",
+			path: "main.go",
+			content: "// Package main is a command.\n" +
+				"package main\n\n" +
+				"//go:generate echo hi\n\n\n" +
+				"/*\n block\n*/\n" +
+				"const url = \"http://x // not a comment\" // trailing\n\n" +
+				"var raw = `a\n\n// kept\n`\n",
+			expected: "package main\n\n" +
+				"//go:generate echo hi\n\n" +
+				"const url = \"http://x // not a comment\"\n\n" +
+				"var raw = `a\n\n// kept\n`\n",
+		},
+		{
+			name:     "JS",
+			path:     "web/app.ts",
+			content:  "/** doc */\nconst a = 'it''s'; // x\nconst b = `/* ${a} */`;\n",
+			expected: "const a = 'it''s';\nconst b = `/* ${a} */`;\n",
+		},
+		{
+			name:     "JSON",
+			path:     "config.json",
+			content:  "{\n  // comment\n  \"url\": \"http://x\"\n}\n",
+			expected: "{\n  \"url\": \"http://x\"\n}\n",
+		},
+		{
+			name:     "SQL",
+			path:     "schema.sql",
+			content:  "-- users\nCREATE TABLE users (\n  name TEXT DEFAULT '--', /* the name */\n  id INT\n);\n",
+			expected: "CREATE TABLE users (\n  name TEXT DEFAULT '--',\n  id INT\n);\n",
+		},
+		{
+			name:     "Shell",
+			path:     "build.sh",
+			content:  "#!/bin/bash\n# Builds\necho \"# $#\" '#' # done\n",
+			expected: "#!/bin/bash\necho \"# $#\" '#'\n",
+		},
+		{
+			name:     "YAML",
+			path:     "ci.yml",
+			content:  "# CI\nurl: http://x#frag # comment\nname: '#1'\n",
+			expected: "url: http://x#frag\nname: '#1'\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			minified, ok := minifyContent(tc.path, tc.content)
+			assert.True(t, ok)
+			assert.Equal(t, tc.expected, minified)
+		})
+	}
+}
+
+func Test_minifyContent_Unsupported(t *testing.T) {
+	for path, content := range map[string]string{
+		"readme.md": "# Title\n",
+		"broken.go": "package main\n\nvar s = \"unterminated\n",
+	} {
+		minified, ok := minifyContent(path, content)
+		assert.False(t, ok, path)
+		assert.Equal(t, content, minified, path)
+	}
+}
@@ -0,0 +1,112 @@
+package utils_common
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"path/filepath"
+)
+
+// elidedBody stands in for the body of every function of an outline.
+const elidedBody = " { ... }"
+
+// outlinePrinter prints the declarations of an outline the way gofmt does.
+var outlinePrinter = printer.Config{Mode: printer.UseSpaces | printer.TabIndent, Tabwidth: 8}
+
+// isOutlined tells whether the file's contents can be reduced to an outline.
+func isOutlined(path string) bool {
+	return filepath.Ext(path) == ".go"
+}
+
+// outlineGo reduces Go source to its API surface: the package clause, the
+// imports, the constants, variables and types and the signatures of every
+// function and method, along with their doc comments. Function bodies are
+// replaced by "{ ... }", and so are those of function literals in declarations.
+func outlineGo(path string, src string) (string, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, path, src, parser.ParseComments)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+
+	writeDoc(&buf, file.Doc)
+	fmt.Fprintf(&buf, "package %s\n", file.Name.Name)
+
+	for _, decl := range file.Decls {
+		buf.WriteString("\n")
+
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			signature := *d
+			signature.Body = nil
+			if err := outlinePrinter.Fprint(&buf, fset, &printer.CommentedNode{Node: &signature, Comments: file.Comments}); err != nil {
+				return "", err
+			}
+			if d.Body != nil {
+				buf.WriteString(elidedBody)
+			}
+		case *ast.GenDecl:
+			comments := elideFuncLits(d, file.Comments)
+			if err := outlinePrinter.Fprint(&buf, fset, &printer.CommentedNode{Node: d, Comments: comments}); err != nil {
+				return "", err
+			}
+		default:
+			continue
+		}
+
+		buf.WriteString("\n")
+	}
+
+	return buf.String(), nil
+}
+
+// elideFuncLits replaces the bodies of the function literals of a declaration
+// (e.g "var f = func() {...}" or the Run field of a cobra command) by
+// "{ ... }" and returns the comments without the ones of those bodies.
+func elideFuncLits(decl ast.Node, comments []*ast.CommentGroup) []*ast.CommentGroup {
+	var bodies []*ast.BlockStmt
+	ast.Inspect(decl, func(n ast.Node) bool {
+		lit, ok := n.(*ast.FuncLit)
+		if !ok {
+			return true
+		}
+		bodies = append(bodies, lit.Body)
+		lit.Body = &ast.BlockStmt{
+			Lbrace: lit.Body.Lbrace,
+			List:   []ast.Stmt{&ast.ExprStmt{X: &ast.Ident{NamePos: lit.Body.Lbrace, Name: "..."}}},
+			Rbrace: lit.Body.Lbrace,
+		}
+		return false
+	})
+
+	kept := make([]*ast.CommentGroup, 0, len(comments))
+	for _, group := range comments {
+		elided := false
+		for _, body := range bodies {
+			if group.Pos() > body.Lbrace && group.End() <= body.Rbrace {
+				elided = true
+				break
+			}
+		}
+		if !elided {
+			kept = append(kept, group)
+		}
+	}
+	return kept
+}
+
+// writeDoc writes a doc comment as it appears in the source.
+func writeDoc(buf *bytes.Buffer, doc *ast.CommentGroup) {
+	if doc == nil {
+		return
+	}
+	for _, comment := range doc.List {
+		buf.WriteString(comment.Text)
+		buf.WriteString("\n")
+	}
+}
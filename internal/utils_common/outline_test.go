@@ -0,0 +1,119 @@
+package utils_common
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func Test_outlineGo(t *testing.T) {
+	src := `// Package shop sells things.
+package shop
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrEmpty is returned for empty carts.
+var ErrEmpty = errors.New("empty")
+
+// Cart holds items.
+type Cart struct {
+	Items []string // The item names
+	total int
+}
+
+type store interface {
+	Save(c *Cart) error
+}
+
+// Add puts an item in the cart.
+func (c *Cart) Add(item string) {
+	// Not part of the outline
+	c.Items = append(c.Items, item)
+}
+
+func checkout(c *Cart) error {
+	if len(c.Items) == 0 {
+		return ErrEmpty
+	}
+	fmt.Println(c.total)
+	return nil
+}
+`
+
+	expected := `// Package shop sells things.
+package shop
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrEmpty is returned for empty carts.
+var ErrEmpty = errors.New("empty")
+
+// Cart holds items.
+type Cart struct {
+	Items []string // The item names
+	total int
+}
+
+type store interface {
+	Save(c *Cart) error
+}
+
+// Add puts an item in the cart.
+func (c *Cart) Add(item string) { ... }
+
+func checkout(c *Cart) error { ... }
+`
+
+	outline, err := outlineGo("shop.go", src)
+	require.NoError(t, err, "outline")
+	assert.Equal(t, expected, outline)
+}
+
+func Test_outlineGo_Elides_Function_Literals(t *testing.T) {
+	src := `package cli
+
+// double is a literal.
+var double = func(n int) int {
+	// Not part of the outline
+	return n * 2
+}
+
+var rootCmd = &cobra.Command{
+	Use: "root", // The command name
+	Run: func(cmd *cobra.Command, args []string) {
+		fmt.Println(double(1))
+	},
+}
+`
+
+	expected := `package cli
+
+// double is a literal.
+var double = func(n int) int { ... }
+
+var rootCmd = &cobra.Command{
+	Use: "root", // The command name
+	Run: func(cmd *cobra.Command, args []string) { ... },
+}
+`
+
+	outline, err := outlineGo("root.go", src)
+	require.NoError(t, err, "outline")
+	assert.Equal(t, expected, outline)
+}
+
+func Test_outlineGo_Fail_Invalid_Source(t *testing.T) {
+	_, err := outlineGo("broken.go", "package broken\n\nfunc {")
+	require.Error(t, err)
+}
+
+func Test_isOutlined(t *testing.T) {
+	assert.True(t, isOutlined("internal/cli/service.go"))
+	assert.False(t, isOutlined("readme.md"))
+}
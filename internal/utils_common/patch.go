@@ -0,0 +1,484 @@
+package utils_common
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// maxPatchFuzz is the number of context lines that may be ignored at
+// each end of a hunk that does not match otherwise, like patch's fuzz factor.
+const maxPatchFuzz = 2
+
+const rejectExt = ".rej"
+
+// ErrNoPatchFiles is returned for text in which no file diff could be found.
+var ErrNoPatchFiles = errors.New("no file diffs found in the patch")
+
+// hunkHeader matches "@@ -12,7 +12,8 @@", the counts being optional.
+var hunkHeader = regexp.MustCompile(`^@@ -(\d+)(?:,(\d+))? \+(\d+)(?:,(\d+))? @@`)
+
+const (
+	ChangeDeleted = "deleted"
+	ChangeFailed  = "failed"
+)
+
+// PatchOptions are the settings used to apply a unified diff under Root.
+// The diff is read from the clipboard or from stdin when Stdin is set.
+// Check reports whether the diff applies, without writing anything.
+type PatchOptions struct {
+	Root  string `mapstructure:"root" validate:"required" json:"root"`
+	Stdin bool   `mapstructure:"stdin" json:"stdin"`
+	Check bool   `mapstructure:"check" json:"check"`
+}
+
+func (p *PatchOptions) Validate() error {
+	return ValidateStruct(p)
+}
+
+// FilePatch is the diff of a single file. An empty OldPath is a file
+// being created and an empty NewPath one being deleted.
+type FilePatch struct {
+	OldPath string `json:"old_path"`
+	NewPath string `json:"new_path"`
+	Hunks   []Hunk `json:"hunks"`
+}
+
+// Path returns the path of the file the diff applies to.
+func (f *FilePatch) Path() string {
+	if f.NewPath != "" {
+		return f.NewPath
+	}
+	return f.OldPath
+}
+
+// Hunk is a group of changed lines, each prefixed by ' ' for context,
+// '-' for a removed line or '+' for an added one.
+type Hunk struct {
+	OldStart int      `json:"old_start"`
+	Lines    []string `json:"lines"`
+}
+
+// oldLines returns the lines the hunk expects to find.
+func (h *Hunk) oldLines() []string {
+	return h.side('-')
+}
+
+// newLines returns the lines the hunk leaves in their place.
+func (h *Hunk) newLines() []string {
+	return h.side('+')
+}
+
+func (h *Hunk) side(change byte) []string {
+	lines := make([]string, 0, len(h.Lines))
+	for _, line := range h.Lines {
+		if line[0] == ' ' || line[0] == change {
+			lines = append(lines, line[1:])
+		}
+	}
+	return lines
+}
+
+// String renders the hunk in the unified format, with its counts recomputed.
+func (h *Hunk) String() string {
+	old, added := len(h.oldLines()), len(h.newLines())
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("@@ -%d,%d +%d,%d @@\n", h.OldStart, old, h.OldStart, added))
+	for _, line := range h.Lines {
+		sb.WriteString(line + "\n")
+	}
+	return sb.String()
+}
+
+// HunkResult tells where a hunk applied, how far from where it claimed to
+// (Offset, in lines), how many context lines had to be ignored at each end
+// (Fuzz) and whether whitespace differences were ignored.
+type HunkResult struct {
+	Applied    bool `json:"applied"`
+	Line       int  `json:"line"`
+	Offset     int  `json:"offset"`
+	Fuzz       int  `json:"fuzz"`
+	Whitespace bool `json:"whitespace"`
+}
+
+// FilePatchResult is the outcome of a file's diff, with the reject
+// file holding the hunks that failed.
+type FilePatchResult struct {
+	Path     string       `json:"path"`
+	Status   string       `json:"status"`
+	Hunks    []HunkResult `json:"hunks"`
+	Rejected string       `json:"rejected"`
+}
+
+// PatchResult is the outcome of a patch, file by file.
+type PatchResult struct {
+	Root  string            `json:"root"`
+	Files []FilePatchResult `json:"files"`
+}
+
+// Failed returns the number of hunks that did not apply.
+func (p *PatchResult) Failed() int {
+	failed := 0
+	for _, file := range p.Files {
+		for _, hunk := range file.Hunks {
+			if !hunk.Applied {
+				failed++
+			}
+		}
+	}
+	return failed
+}
+
+// ParsePatch extracts the file diffs of a unified diff, as produced by
+// git diff or diff -u. Anything around the diffs (e.g prose or code fences)
+// is ignored and the hunks' line counts are recomputed from their lines,
+// as hand written diffs often get them wrong.
+func ParsePatch(text string) ([]FilePatch, error) {
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+
+	var patches []FilePatch
+	for i := 0; i < len(lines); {
+		if !isFileHeader(lines, i) {
+			i++
+			continue
+		}
+
+		patch := FilePatch{
+			OldPath: patchPath(lines[i][4:], "a/"),
+			NewPath: patchPath(lines[i+1][4:], "b/"),
+		}
+		i += 2
+
+		for i < len(lines) && strings.HasPrefix(lines[i], "@@") {
+			hunk := Hunk{}
+			if m := hunkHeader.FindStringSubmatch(lines[i]); m != nil {
+				hunk.OldStart, _ = strconv.Atoi(m[1])
+			}
+			i++
+
+			for ; i < len(lines) && !isFileHeader(lines, i); i++ {
+				line := lines[i]
+				if line == "" {
+					line = " " // Blank context lines often lose their space
+				}
+				if line[0] == '\\' {
+					continue // "\ No newline at end of file"
+				}
+				if line[0] != ' ' && line[0] != '-' && line[0] != '+' {
+					break
+				}
+				hunk.Lines = append(hunk.Lines, line)
+			}
+
+			for len(hunk.Lines) > 0 && hunk.Lines[len(hunk.Lines)-1] == " " {
+				hunk.Lines = hunk.Lines[:len(hunk.Lines)-1]
+			}
+			if len(hunk.Lines) > 0 {
+				patch.Hunks = append(patch.Hunks, hunk)
+			}
+		}
+
+		if len(patch.Hunks) > 0 {
+			patches = append(patches, patch)
+		}
+	}
+
+	if len(patches) == 0 {
+		return nil, ErrNoPatchFiles
+	}
+	return patches, nil
+}
+
+// isFileHeader tells whether lines[i] starts a "--- old", "+++ new" header.
+func isFileHeader(lines []string, i int) bool {
+	return strings.HasPrefix(lines[i], "--- ") && i+1 < len(lines) && strings.HasPrefix(lines[i+1], "+++ ")
+}
+
+// patchPath returns the path of a file header, without its timestamp
+// or its "a/" or "b/" prefix. /dev/null is returned as an empty path.
+func patchPath(header string, prefix string) string {
+	name := strings.TrimSpace(strings.SplitN(header, "\t", 2)[0])
+	if name == "/dev/null" {
+		return ""
+	}
+	return strings.TrimPrefix(name, prefix)
+}
+
+// ApplyPatch applies the file diffs under root. Every hunk is looked for
+// around the line it claims, drifting as far as needed, first exactly, then
+// ignoring whitespace differences, then ignoring up to maxPatchFuzz context
+// lines at its ends. Files keep the hunks that applied, while the failed
+// ones are written to a ".rej" file next to them. Nothing is written when
+// check is set.
+//
+// The whole patch is refused when one of its paths escapes the root.
+func ApplyPatch(root string, patches []FilePatch, check bool) (*PatchResult, error) {
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return nil, fmt.Errorf("root: %v", err)
+	}
+	realRoot, err := filepath.EvalSymlinks(absRoot)
+	if err != nil {
+		return nil, fmt.Errorf("root: %v", err)
+	}
+
+	type patchedFile struct {
+		abs     string
+		content string
+		mode    os.FileMode
+		reject  string
+	}
+
+	result := PatchResult{
+		Root:  absRoot,
+		Files: make([]FilePatchResult, 0, len(patches)),
+	}
+	patched := make([]patchedFile, 0, len(patches))
+
+	for _, patch := range patches {
+		name, err := cleanBundlePath(patch.Path())
+		if err != nil {
+			return nil, err
+		}
+		abs, err := resolveWithin(absRoot, realRoot, name)
+		if err != nil {
+			return nil, err
+		}
+
+		file := patchedFile{abs: abs, mode: 0644}
+		current := ""
+		info, err := os.Stat(abs)
+		exists := err == nil
+		switch {
+		case os.IsNotExist(err) && patch.OldPath == "":
+			// Created by the patch
+		case err != nil:
+			return nil, fmt.Errorf("stat %s: %v", name, err)
+		case info.IsDir():
+			return nil, fmt.Errorf("'%s' is a directory", name)
+		default:
+			data, err := os.ReadFile(abs)
+			if err != nil {
+				return nil, fmt.Errorf("read %s: %v", name, err)
+			}
+			current = string(data)
+			file.mode = info.Mode().Perm()
+		}
+
+		var rejected []Hunk
+		fileResult := FilePatchResult{Path: name, Status: ChangeModified}
+		if patch.OldPath == "" && exists {
+			// Creating a file that exists fails as a whole
+			rejected = patch.Hunks
+			fileResult.Hunks = make([]HunkResult, len(patch.Hunks))
+		} else {
+			file.content, fileResult.Hunks, rejected = applyHunks(current, patch.Hunks)
+		}
+
+		applied := len(patch.Hunks) - len(rejected)
+		switch {
+		case applied == 0:
+			fileResult.Status = ChangeFailed
+		case patch.OldPath == "":
+			fileResult.Status = ChangeNew
+		case patch.NewPath == "" && file.content == "":
+			fileResult.Status = ChangeDeleted
+		}
+
+		if len(rejected) > 0 {
+			var sb strings.Builder
+			sb.WriteString(fmt.Sprintf("--- a/%s\n+++ b/%s\n", name, name))
+			for _, hunk := range rejected {
+				sb.WriteString(hunk.String())
+			}
+			file.reject = sb.String()
+			fileResult.Rejected = name + rejectExt
+		}
+
+		result.Files = append(result.Files, fileResult)
+		patched = append(patched, file)
+	}
+
+	if check {
+		return &result, nil
+	}
+
+	for i, file := range patched {
+		var err error
+		switch result.Files[i].Status {
+		case ChangeFailed:
+		case ChangeDeleted:
+			err = os.Remove(file.abs)
+		default:
+			if err = os.MkdirAll(filepath.Dir(file.abs), 0755); err == nil {
+				err = os.WriteFile(file.abs, []byte(file.content), file.mode)
+			}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("write %s: %v", result.Files[i].Path, err)
+		}
+
+		if file.reject != "" {
+			if err := os.WriteFile(file.abs+rejectExt, []byte(file.reject), 0644); err != nil {
+				return nil, fmt.Errorf("write %s: %v", result.Files[i].Rejected, err)
+			}
+		}
+	}
+
+	return &result, nil
+}
+
+// applyHunks applies the hunks to the content in order and returns the
+// patched content, the result of every hunk and the hunks that failed.
+func applyHunks(content string, hunks []Hunk) (string, []HunkResult, []Hunk) {
+	lines := strings.SplitAfter(content, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	trailingNewline := content == "" || strings.HasSuffix(content, "\n")
+	for i := range lines {
+		lines[i] = strings.TrimSuffix(lines[i], "\n")
+	}
+
+	results := make([]HunkResult, 0, len(hunks))
+	var rejected []Hunk
+
+	offset, next := 0, 0
+	for _, hunk := range hunks {
+		start := hunk.OldStart - 1
+		if len(hunk.oldLines()) == 0 {
+			start = hunk.OldStart // Inserts after the line
+		}
+
+		result := HunkResult{}
+		for fuzz := 0; fuzz <= maxPatchFuzz && !result.Applied; fuzz++ {
+			trimmed, lead, ok := trimContext(hunk, fuzz)
+			if !ok {
+				break
+			}
+			old := trimmed.oldLines()
+
+			for _, whitespace := range []bool{false, true} {
+				pos, found := locateLines(lines, old, start+offset+lead, next, whitespace)
+				if !found {
+					continue
+				}
+
+				lines = spliceHunk(lines, pos, trimmed)
+				result = HunkResult{
+					Applied:    true,
+					Line:       pos - lead + 1,
+					Offset:     pos - lead - start,
+					Fuzz:       fuzz,
+					Whitespace: whitespace,
+				}
+				offset = result.Offset
+				next = pos + len(trimmed.newLines())
+				break
+			}
+		}
+
+		if !result.Applied {
+			rejected = append(rejected, hunk)
+		}
+		results = append(results, result)
+	}
+
+	patched := strings.Join(lines, "\n")
+	if len(lines) > 0 && trailingNewline {
+		patched += "\n"
+	}
+	return patched, results, rejected
+}
+
+// trimContext drops up to fuzz context lines from both ends of the hunk
+// and returns the number dropped from its start. It fails when the hunk
+// has no context lines left to drop.
+func trimContext(hunk Hunk, fuzz int) (Hunk, int, bool) {
+	lines, lead := hunk.Lines, 0
+	for i := 0; i < fuzz; i++ {
+		trimmed := false
+		if len(lines) > 0 && lines[0][0] == ' ' {
+			lines = lines[1:]
+			lead++
+			trimmed = true
+		}
+		if len(lines) > 0 && lines[len(lines)-1][0] == ' ' {
+			lines = lines[:len(lines)-1]
+			trimmed = true
+		}
+		if !trimmed {
+			return hunk, 0, false
+		}
+	}
+	return Hunk{OldStart: hunk.OldStart, Lines: lines}, lead, true
+}
+
+// locateLines finds where the old lines appear at or after from, the
+// closest to expected, comparing the lines with their whitespace collapsed
+// when whitespace is set.
+func locateLines(lines []string, old []string, expected int, from int, whitespace bool) (int, bool) {
+	last := len(lines) - len(old)
+	if expected < from {
+		expected = from
+	}
+	if expected > last {
+		expected = last
+	}
+
+	matches := func(pos int) bool {
+		for i, line := range old {
+			if whitespace {
+				if collapseSpace(lines[pos+i]) != collapseSpace(line) {
+					return false
+				}
+				continue
+			}
+			if lines[pos+i] != line {
+				return false
+			}
+		}
+		return true
+	}
+
+	for distance := 0; expected-distance >= from || expected+distance <= last; distance++ {
+		if pos := expected - distance; pos >= from && pos <= last && matches(pos) {
+			return pos, true
+		}
+		if pos := expected + distance; distance > 0 && pos >= from && pos <= last && matches(pos) {
+			return pos, true
+		}
+	}
+	return 0, false
+}
+
+// spliceHunk replaces the lines the hunk found at pos by its new lines.
+// Context lines keep the file's version, which may differ in whitespace.
+func spliceHunk(lines []string, pos int, hunk Hunk) []string {
+	out := make([]string, 0, len(lines)+len(hunk.Lines))
+	out = append(out, lines[:pos]...)
+
+	cursor := pos
+	for _, line := range hunk.Lines {
+		switch line[0] {
+		case ' ':
+			out = append(out, lines[cursor])
+			cursor++
+		case '-':
+			cursor++
+		case '+':
+			out = append(out, line[1:])
+		}
+	}
+
+	return append(out, lines[cursor:]...)
+}
+
+// collapseSpace trims the line and collapses its runs of whitespace.
+func collapseSpace(line string) string {
+	return strings.Join(strings.Fields(line), " ")
+}
@@ -0,0 +1,172 @@
+package utils_common
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// testNumberedLines returns the lines "line 1" to "line n".
+func testNumberedLines(n int) []string {
+	lines := make([]string, n)
+	for i := range lines {
+		lines[i] = fmt.Sprintf("line %d", i+1)
+	}
+	return lines
+}
+
+func TestParsePatch(t *testing.T) {
+	text := "Here is the fix:\n\n```diff\n" +
+		"diff --git a/main.go b/main.go\n" +
+		"--- a/main.go\t2024-01-01 00:00:00\n" +
+		"+++ b/main.go\n" +
+		"@@ -1,3 +1,3 @@ func main() {\n" +
+		" a\n" +
+		"-b\n" +
+		"+B\n" +
+		"\n" +
+		"\\ No newline at end of file\n" +
+		"--- /dev/null\n" +
+		"+++ b/new.txt\n" +
+		"@@ @@\n" +
+		"+new\n" +
+		"```\n"
+
+	patches, err := ParsePatch(text)
+	require.NoError(t, err)
+
+	assert.Equal(t, []FilePatch{
+		{
+			OldPath: "main.go",
+			NewPath: "main.go",
+			Hunks:   []Hunk{{OldStart: 1, Lines: []string{" a", "-b", "+B"}}},
+		},
+		{
+			NewPath: "new.txt",
+			Hunks:   []Hunk{{Lines: []string{"+new"}}},
+		},
+	}, patches)
+
+	_, err = ParsePatch("no diff here")
+	require.ErrorIs(t, err, ErrNoPatchFiles)
+}
+
+func Test_applyHunks(t *testing.T) {
+	lines := testNumberedLines(40)
+	content := strings.Join(lines, "\n") + "\n"
+
+	testCases := []struct {
+		name     string
+		content  string
+		hunk     Hunk
+		expected HunkResult
+	}{
+		{
+			name:     "Exact",
+			content:  content,
+			hunk:     Hunk{OldStart: 20, Lines: []string{" " + lines[19], "-" + lines[20], "+changed", " " + lines[21]}},
+			expected: HunkResult{Applied: true, Line: 20},
+		},
+		{
+			name:     "Drifted",
+			content:  content,
+			hunk:     Hunk{OldStart: 3, Lines: []string{" " + lines[19], "-" + lines[20], "+changed", " " + lines[21]}},
+			expected: HunkResult{Applied: true, Line: 20, Offset: 17},
+		},
+		{
+			name:     "Whitespace",
+			content:  strings.Replace(content, lines[20], "  "+lines[20]+"\t", 1),
+			hunk:     Hunk{OldStart: 20, Lines: []string{" " + lines[19], "-" + lines[20], "+changed", " " + lines[21]}},
+			expected: HunkResult{Applied: true, Line: 20, Whitespace: true},
+		},
+		{
+			name:     "Fuzz",
+			content:  content,
+			hunk:     Hunk{OldStart: 19, Lines: []string{" stale", " " + lines[19], "-" + lines[20], "+changed", " " + lines[21], " stale"}},
+			expected: HunkResult{Applied: true, Line: 19, Fuzz: 1},
+		},
+		{
+			name:     "Rejected",
+			content:  content,
+			hunk:     Hunk{OldStart: 20, Lines: []string{"-missing", "+changed"}},
+			expected: HunkResult{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			patched, results, rejected := applyHunks(tc.content, []Hunk{tc.hunk})
+			require.Len(t, results, 1)
+			assert.Equal(t, tc.expected, results[0])
+
+			if !tc.expected.Applied {
+				assert.Equal(t, []Hunk{tc.hunk}, rejected)
+				assert.Equal(t, tc.content, patched)
+				return
+			}
+			assert.Empty(t, rejected)
+			assert.Contains(t, patched, "\nchanged\n")
+			assert.NotContains(t, patched, "\n"+lines[20]+"\n")
+		})
+	}
+}
+
+func TestApplyPatch(t *testing.T) {
+	root := t.TempDir()
+	write := func(name, content string) {
+		require.NoError(t, os.WriteFile(filepath.Join(root, name), []byte(content), 0644))
+	}
+	read := func(name string) string {
+		data, err := os.ReadFile(filepath.Join(root, name))
+		require.NoError(t, err)
+		return string(data)
+	}
+	write("a.txt", "one\ntwo\nthree\n")
+	write("gone.txt", "bye\n")
+
+	patches, err := ParsePatch("" +
+		"--- a/a.txt\n+++ b/a.txt\n" +
+		"@@ -1,2 +1,2 @@\n one\n-two\n+TWO\n" +
+		"@@ -3 +3 @@\n-four\n+FOUR\n" +
+		"--- /dev/null\n+++ b/sub/new.txt\n@@ -0,0 +1 @@\n+new\n" +
+		"--- a/gone.txt\n+++ /dev/null\n@@ -1 +0,0 @@\n-bye\n")
+	require.NoError(t, err)
+
+	t.Run("Check", func(t *testing.T) {
+		result, err := ApplyPatch(root, patches, true)
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.Failed())
+		assert.Equal(t, "one\ntwo\nthree\n", read("a.txt"), "nothing is written")
+		assert.NoFileExists(t, filepath.Join(root, "a.txt.rej"))
+	})
+
+	t.Run("Apply", func(t *testing.T) {
+		result, err := ApplyPatch(root, patches, false)
+		require.NoError(t, err)
+
+		require.Len(t, result.Files, 3)
+		assert.Equal(t, ChangeModified, result.Files[0].Status)
+		assert.Equal(t, []bool{true, false}, []bool{result.Files[0].Hunks[0].Applied, result.Files[0].Hunks[1].Applied})
+		assert.Equal(t, "a.txt.rej", result.Files[0].Rejected)
+		assert.Equal(t, ChangeNew, result.Files[1].Status)
+		assert.Equal(t, ChangeDeleted, result.Files[2].Status)
+
+		assert.Equal(t, "one\nTWO\nthree\n", read("a.txt"))
+		assert.Equal(t, "--- a/a.txt\n+++ b/a.txt\n@@ -3,1 +3,1 @@\n-four\n+FOUR\n", read("a.txt.rej"))
+		assert.Equal(t, "new\n", read("sub/new.txt"))
+		assert.NoFileExists(t, filepath.Join(root, "gone.txt"))
+	})
+}
+
+func TestApplyPatch_Refuses_Escaping_Paths(t *testing.T) {
+	patches, err := ParsePatch("--- a/../x.txt\n+++ b/../x.txt\n@@ -1 +1 @@\n-a\n+b\n")
+	require.NoError(t, err)
+
+	_, err = ApplyPatch(t.TempDir(), patches, true)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "refusing")
+}
@@ -3,12 +3,12 @@ package utils_common
 import (
 	"encoding/json"
 	"fmt"
-	"github.com/atotto/clipboard"
 	"reflect"
 )
 
-// GetJSONAndCopyToClipboard generates a JSON string from the input and copies it to the clipboard.
-func GetJSONAndCopyToClipboard(i ...interface{}) string {
+// GetJSONAndCopyToClipboard generates a JSON string from the input and copies it to the clipboard,
+// recording it into the history when given.
+func GetJSONAndCopyToClipboard(history *History, i ...interface{}) string {
 	if len(i) == 0 || (len(i) == 1 && i[0] == nil) {
 		fmt.Println("No input provided or input is nil")
 		return ""
@@ -21,7 +21,7 @@ func GetJSONAndCopyToClipboard(i ...interface{}) string {
 		return ""
 	}
 
-	err := clipboard.WriteAll(jsonStr)
+	err := CopyToClipboard(jsonStr, history)
 	if err != nil {
 		fmt.Printf("Error copying to clipboard: %v\n", err)
 		return ""
@@ -0,0 +1,30 @@
+package utils_common
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestGetJSONAndCopyToClipboard_Records_History(t *testing.T) {
+	history, _ := testHistory(t, 0, 0)
+
+	var clipped string
+	defer func(write func(string) error) { clipboardWrite = write }(clipboardWrite)
+	clipboardWrite = func(text string) error {
+		clipped = text
+		return nil
+	}
+
+	jsonStr := GetJSONAndCopyToClipboard(history, map[string]int{"a": 1})
+	assert.Equal(t, `[{"a":1}]`, jsonStr)
+	assert.Equal(t, jsonStr, clipped)
+
+	entries, err := history.List()
+	require.NoError(t, err, "list")
+	require.Len(t, entries, 1)
+
+	_, content, err := history.Get(entries[0].ID)
+	require.NoError(t, err, "get")
+	assert.Equal(t, jsonStr, content)
+}
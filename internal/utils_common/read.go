@@ -0,0 +1,113 @@
+package utils_common
+
+import (
+	"context"
+	"os"
+	"sync"
+)
+
+// maxReadWorkers bounds the number of files read at the same time.
+const maxReadWorkers = 16
+
+// loadedFile is the contents of a selected file, prepared for the bundle.
+type loadedFile struct {
+	Size       int64
+	Content    string
+	Binary     bool
+	Redactions []Redaction
+	Saved      int64 // Bytes stripped by the minification
+	First      int   // Line number of the first line, when only a snippet of the file is loaded
+}
+
+// loadFiles reads and prepares the selected files with a bounded pool of
+// workers. The files are returned in the order they were selected in, so
+// the bundle is the same no matter how the reads interleave.
+//
+// It stops handing out files as soon as the context is done.
+func loadFiles(ctx context.Context, files []selectedFile, opts *ClipOptions) ([]loadedFile, error) {
+	workers := maxReadWorkers
+	if len(files) < workers {
+		workers = len(files)
+	}
+
+	loaded := make([]loadedFile, len(files))
+	jobs := make(chan int)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				loaded[i] = loadFile(files[i], opts)
+			}
+		}()
+	}
+
+feed:
+	for i := range files {
+		select {
+		case <-ctx.Done():
+			break feed
+		case jobs <- i:
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return loaded, nil
+}
+
+// loadFile reads a file and replaces binary contents and preserved symbolic
+// links by a placeholder. It masks the secrets of text contents unless
+// NoRedact is set, reduces Go files to their outline when Outline is set
+// and strips comments when Minify is set. A file that cannot be read is
+// bundled empty, while one that cannot be parsed is bundled whole.
+func loadFile(selected selectedFile, opts *ClipOptions) loadedFile {
+	if selected.Link != "" {
+		placeholder := symlinkPlaceholder(selected.Link)
+		return loadedFile{Size: int64(len(placeholder)), Content: placeholder}
+	}
+
+	data, err := os.ReadFile(selected.Path)
+	if err != nil {
+		logger.Warnf("reading file '%s' failed: %s\n", selected.Rel, err)
+	}
+
+	file := loadedFile{
+		Size:    int64(len(data)),
+		Content: string(data),
+	}
+
+	if mimeType, binary := detectBinary(data); binary {
+		file.Binary = true
+		file.Content = binaryPlaceholder(len(data), mimeType)
+		return file
+	}
+
+	if !opts.NoRedact {
+		file.Content, file.Redactions = redactSecrets(selected.Rel, file.Content)
+	}
+
+	if opts.Outline && isOutlined(selected.Rel) {
+		outline, err := outlineGo(selected.Rel, file.Content)
+		if err != nil {
+			logger.Warnf("outlining file '%s' failed, clipping it whole: %s\n", selected.Rel, err)
+		} else {
+			file.Content = outline
+		}
+	}
+
+	if opts.Minify {
+		if minified, ok := minifyContent(selected.Rel, file.Content); ok {
+			file.Saved = int64(len(file.Content) - len(minified))
+			file.Content = minified
+		}
+	}
+
+	return file
+}
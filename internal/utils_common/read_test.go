@@ -0,0 +1,53 @@
+package utils_common
+
+import (
+	"context"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func Test_loadFiles_Keeps_Order(t *testing.T) {
+	arrTestDir, cleanup := testCreateTestDir(t)
+	defer cleanup()
+
+	root := filepath.Join(arrTestDir...)
+
+	var files []selectedFile
+	for i := 0; i < 200; i++ {
+		name := fmt.Sprintf("file_%03d.txt", i)
+		err := os.WriteFile(filepath.Join(root, name), []byte(name), 0644)
+		require.NoError(t, err, "write %s", name)
+		files = append(files, selectedFile{Path: filepath.Join(root, name), Rel: name})
+	}
+
+	loaded, err := loadFiles(context.Background(), files, &ClipOptions{})
+	require.NoError(t, err, "load files")
+	require.Len(t, loaded, len(files))
+
+	for i, file := range files {
+		assert.Equal(t, file.Rel, loaded[i].Content)
+	}
+}
+
+func Test_loadFiles_Fail_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := loadFiles(ctx, []selectedFile{{Path: "missing", Rel: "missing"}}, &ClipOptions{})
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func TestBuildBundle_Fail_Canceled(t *testing.T) {
+	arrTestDir, cleanup := testCreateTestDir(t)
+	defer cleanup()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := BuildBundle(ctx, &ClipOptions{Paths: []string{filepath.Join(arrTestDir...)}})
+	require.ErrorContains(t, err, context.Canceled.Error())
+}
@@ -0,0 +1,343 @@
+package utils_common
+
+import (
+	"math"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// redactedMask replaces every secret found in the contents.
+const redactedMask = "[REDACTED]"
+
+const (
+	secretKindPrivateKey  = "private key"
+	secretKindAssignment  = "secret assignment"
+	secretKindHighEntropy = "high entropy string"
+)
+
+// Redaction is a secret that was masked in a file's contents.
+type Redaction struct {
+	Path string `json:"path"`
+	Line int    `json:"line"`
+	Kind string `json:"kind"`
+}
+
+// secretToken is a well known credential format, recognizable by its prefix.
+type secretToken struct {
+	kind string
+	re   *regexp.Regexp
+}
+
+var secretTokens = []secretToken{
+	{kind: "AWS access key", re: regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`)},
+	{kind: "GitHub token", re: regexp.MustCompile(`\b(?:gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{22,})\b`)},
+	{kind: "GitLab token", re: regexp.MustCompile(`\bglpat-[A-Za-z0-9_-]{20,}`)},
+	{kind: "Slack token", re: regexp.MustCompile(`\bxox[abprs]-[A-Za-z0-9-]{10,}`)},
+	{kind: "Stripe key", re: regexp.MustCompile(`\b[rs]k_(?:live|test)_[A-Za-z0-9]{16,}\b`)},
+	{kind: "OpenAI key", re: regexp.MustCompile(`\bsk-(?:proj-|ant-)?[A-Za-z0-9_-]{20,}`)},
+	{kind: "Google API key", re: regexp.MustCompile(`\bAIza[0-9A-Za-z_-]{35}\b`)},
+	{kind: "JSON web token", re: regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{8,}\.eyJ[A-Za-z0-9_-]{8,}\.[A-Za-z0-9_-]{8,}`)},
+}
+
+var (
+	pemBegin = regexp.MustCompile(`-----BEGIN [A-Z0-9 ]*PRIVATE KEY[A-Z0-9 ]*-----`)
+	pemEnd   = regexp.MustCompile(`-----END [A-Z0-9 ]*PRIVATE KEY[A-Z0-9 ]*-----`)
+
+	// settingName matches the names of settings, checked by isSecretName.
+	settingName = `[A-Za-z0-9_.-]+`
+
+	// quotedAssignment matches a setting assigned a quoted literal, in any
+	// language (e.g `apiKey = "..."` or `"api_key": "..."`).
+	quotedAssignment = regexp.MustCompile(`((` + settingName + `)["']?\s*(?::=|=|:)\s*)(["'])([^"'\s]{4,})(["'])`)
+
+	// configAssignment matches a setting assigned at the start of a line of
+	// configuration (e.g `DB_PASS=...` in a .env file or `token: ...` in YAML).
+	configAssignment = regexp.MustCompile(`^(\s*(?:export\s+)?(` + settingName + `)\s*[=:]\s*)(["']?)([^"'\s#]+)(["']?)`)
+
+	// entropyCandidate matches the runs of characters secrets are made of.
+	entropyCandidate = regexp.MustCompile(`[A-Za-z0-9+/_=-]{24,}`)
+)
+
+// configExtensions are the files holding settings, rather than code,
+// where unquoted assignments are taken as secrets.
+var configExtensions = map[string]bool{
+	"":            true,
+	".env":        true,
+	".ini":        true,
+	".cfg":        true,
+	".conf":       true,
+	".properties": true,
+	".yaml":       true,
+	".yml":        true,
+	".toml":       true,
+	".sh":         true,
+}
+
+// checksumFiles list hashes of dependencies, which look random, but are public.
+var checksumFiles = map[string]bool{
+	"go.sum":            true,
+	"package-lock.json": true,
+	"yarn.lock":         true,
+	"pnpm-lock.yaml":    true,
+	"Cargo.lock":        true,
+	"poetry.lock":       true,
+	"composer.lock":     true,
+}
+
+// secretWords are the words naming a setting that holds a credential.
+var secretWords = map[string]bool{
+	"pass":        true,
+	"passwd":      true,
+	"password":    true,
+	"passphrase":  true,
+	"pwd":         true,
+	"secret":      true,
+	"token":       true,
+	"credential":  true,
+	"credentials": true,
+	"apikey":      true,
+	"accesskey":   true,
+	"privatekey":  true,
+	"secretkey":   true,
+	"signingkey":  true,
+}
+
+// keyQualifiers are the words making a "key" a credential, e.g "api_key".
+var keyQualifiers = map[string]bool{
+	"api":     true,
+	"access":  true,
+	"auth":    true,
+	"private": true,
+	"secret":  true,
+	"signing": true,
+}
+
+// minCodeSecretLength is the length below which a quoted value assigned
+// in code is taken as an ordinary string rather than a secret.
+const minCodeSecretLength = 8
+
+// minSecretEntropy is the Shannon entropy (bits per character) above which
+// a long run of mixed case letters and digits is taken as a random secret.
+const minSecretEntropy = 4.0
+
+// redactSecrets masks the secrets found in the contents of the file at path
+// and returns the masked contents with the (1-based) lines that were redacted.
+func redactSecrets(path string, content string) (string, []Redaction) {
+	name := filepath.Base(path)
+	if checksumFiles[name] {
+		return content, nil
+	}
+	configFile := isConfigFile(name)
+
+	var redactions []Redaction
+	lines := strings.Split(content, "\n")
+	out := make([]string, 0, len(lines))
+
+	inKey := false
+	for i, line := range lines {
+		// Every line of a private key is masked on its own, so the
+		// contents keep their line numbers.
+		if inKey {
+			if end := pemEnd.FindStringIndex(line); end != nil {
+				out = append(out, maskBefore(line, end[0]))
+				inKey = false
+			} else {
+				out = append(out, redactedMask)
+			}
+			continue
+		}
+
+		if loc := pemBegin.FindStringIndex(line); loc != nil {
+			redactions = append(redactions, Redaction{Path: path, Line: i + 1, Kind: secretKindPrivateKey})
+			begin, rest := line[:loc[1]], line[loc[1]:]
+			if end := pemEnd.FindStringIndex(rest); end != nil {
+				out = append(out, begin+maskBefore(rest, end[0]))
+			} else {
+				out = append(out, begin+maskBefore(rest, len(rest)))
+				inKey = true
+			}
+			continue
+		}
+
+		masked, kind := redactLine(line, configFile)
+		if kind != "" {
+			redactions = append(redactions, Redaction{Path: path, Line: i + 1, Kind: kind})
+		}
+		out = append(out, masked)
+	}
+
+	if len(redactions) == 0 {
+		return content, nil
+	}
+
+	return strings.Join(out, "\n"), redactions
+}
+
+// maskBefore masks the part of a private key line before the index,
+// keeping what follows (e.g the END marker) or the line when empty.
+func maskBefore(line string, index int) string {
+	if strings.TrimSpace(line[:index]) == "" {
+		return line
+	}
+	return redactedMask + line[index:]
+}
+
+// redactLine masks the secrets of a single line and returns the
+// kind of the first secret found or an empty string when there are none.
+func redactLine(line string, configFile bool) (string, string) {
+	kind := ""
+	found := func(k string) {
+		if kind == "" {
+			kind = k
+		}
+	}
+
+	for _, token := range secretTokens {
+		if token.re.MatchString(line) {
+			line = token.re.ReplaceAllString(line, redactedMask)
+			found(token.kind)
+		}
+	}
+
+	maskValue := func(re *regexp.Regexp) {
+		line = re.ReplaceAllStringFunc(line, func(match string) string {
+			groups := re.FindStringSubmatch(match)
+			if !isSecretName(groups[2]) || isPlaceholderValue(groups[4]) {
+				return match
+			}
+			if !configFile && !looksLikeSecretValue(groups[4]) {
+				return match
+			}
+			found(secretKindAssignment)
+			return groups[1] + groups[3] + redactedMask + groups[5]
+		})
+	}
+	maskValue(quotedAssignment)
+	if configFile {
+		maskValue(configAssignment)
+	}
+
+	line = entropyCandidate.ReplaceAllStringFunc(line, func(match string) string {
+		if !looksRandom(match) {
+			return match
+		}
+		found(secretKindHighEntropy)
+		return redactedMask
+	})
+
+	return line, kind
+}
+
+// isConfigFile tells whether the file name is one of a configuration file.
+func isConfigFile(name string) bool {
+	if strings.HasPrefix(name, ".env") {
+		return true
+	}
+	return configExtensions[strings.ToLower(filepath.Ext(name))]
+}
+
+// isSecretName tells whether a setting's name holds a credential, one of
+// its words being a secret word (e.g "DB_PASS" or "dbPassword", but not
+// "bypass" or "SortByTokens") or a qualified "key" (e.g "api_key").
+func isSecretName(name string) bool {
+	words := nameWords(name)
+	for i, word := range words {
+		if secretWords[word] {
+			return true
+		}
+		if word == "key" && i > 0 && keyQualifiers[words[i-1]] {
+			return true
+		}
+	}
+	return false
+}
+
+// nameWords splits a name into its lower case words, on "_", ".", "-",
+// and camel case boundaries, e.g "authToken" or "APIKey" into two.
+func nameWords(name string) []string {
+	var words []string
+	for _, part := range strings.FieldsFunc(name, func(r rune) bool {
+		return r == '_' || r == '.' || r == '-'
+	}) {
+		runes := []rune(part)
+		start := 0
+		for i := 1; i < len(runes); i++ {
+			lowerBefore := unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1])
+			acronymEnd := unicode.IsUpper(runes[i-1]) && i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsUpper(runes[i]) && (lowerBefore || acronymEnd) {
+				words = append(words, strings.ToLower(string(runes[start:i])))
+				start = i
+			}
+		}
+		words = append(words, strings.ToLower(string(runes[start:])))
+	}
+	return words
+}
+
+// looksLikeSecretValue tells whether a quoted value assigned in code can
+// be a secret, being long enough and more than a single plain word
+// (e.g "hunter22", but not "secret" or "Authorization").
+func looksLikeSecretValue(value string) bool {
+	if len(value) < minCodeSecretLength {
+		return false
+	}
+	for _, r := range value {
+		if !unicode.IsLetter(r) {
+			return true
+		}
+	}
+	return false
+}
+
+// isPlaceholderValue tells whether an assigned value is obviously not
+// a secret, e.g a reference to another variable or the mask itself.
+func isPlaceholderValue(value string) bool {
+	switch {
+	case value == redactedMask,
+		strings.HasPrefix(value, "$"),
+		strings.HasPrefix(value, "<"),
+		strings.HasPrefix(value, "%"),
+		strings.HasPrefix(value, "{{"):
+		return true
+	}
+	return false
+}
+
+// looksRandom tells whether s has the character mix and entropy of a
+// generated secret (upper and lower case letters and digits).
+func looksRandom(s string) bool {
+	var upper, lower, digit bool
+	for _, r := range s {
+		switch {
+		case r >= 'A' && r <= 'Z':
+			upper = true
+		case r >= 'a' && r <= 'z':
+			lower = true
+		case r >= '0' && r <= '9':
+			digit = true
+		}
+	}
+	if !upper || !lower || !digit {
+		return false
+	}
+	return shannonEntropy(s) >= minSecretEntropy
+}
+
+// shannonEntropy returns the average number of bits per character of s.
+func shannonEntropy(s string) float64 {
+	counts := make(map[rune]int)
+	total := 0
+	for _, r := range s {
+		counts[r]++
+		total++
+	}
+
+	entropy := 0.0
+	for _, count := range counts {
+		p := float64(count) / float64(total)
+		entropy -= p * math.Log2(p)
+	}
+	return entropy
+}
@@ -0,0 +1,276 @@
+package utils_common
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// selectedFile is a file picked for a bundle.
+type selectedFile struct {
+	Path string // Absolute path on disk
+	Rel  string // Slash separated path, relative to the base
+	Link string // Target of a preserved symbolic link
+}
+
+// selectFiles picks the files of every path in the options. Directories are
+// walked (or asked to git), while files given explicitly are always selected.
+//
+// The files are de-duplicated and ordered like a walk of their common base
+// directory would order them. The base is returned alongside, with the
+// files the filter left out. Symbolic links found below the paths are
+// handled by the Symlinks policy.
+func selectFiles(ctx context.Context, opts *ClipOptions) (string, []selectedFile, []SkippedFile, error) {
+	if len(opts.GoPackages) > 0 {
+		return selectGoPackageFiles(ctx, opts)
+	}
+
+	absPaths := make([]string, 0, len(opts.Paths))
+	for _, path := range opts.Paths {
+		abs, err := filepath.Abs(path)
+		if err != nil {
+			return "", nil, nil, err
+		}
+		absPaths = append(absPaths, abs)
+	}
+
+	base, err := commonBase(absPaths)
+	if err != nil {
+		return "", nil, nil, err
+	}
+
+	filter, err := newFileFilter(base, opts)
+	if err != nil {
+		return "", nil, nil, fmt.Errorf("filter: %v", err)
+	}
+
+	seen := make(map[string]bool)
+	var files []selectedFile
+
+	add := func(path string, explicit bool) error {
+		if seen[path] {
+			return nil
+		}
+		seen[path] = true
+
+		rel, err := filter.relative(path)
+		if err != nil {
+			return err
+		}
+		file := selectedFile{Path: path, Rel: rel}
+		if !explicit && symlinkPolicy(opts.Symlinks) == SymlinkPreserve {
+			if file.Link, err = readSymlink(path); err != nil {
+				return err
+			}
+		}
+		files = append(files, file)
+		return nil
+	}
+
+	for _, path := range absPaths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return "", nil, nil, err
+		}
+
+		if !info.IsDir() {
+			if err := add(path, true); err != nil {
+				return "", nil, nil, err
+			}
+			continue
+		}
+
+		if err := filter.enter(path); err != nil {
+			return "", nil, nil, err
+		}
+
+		var found []string
+		if opts.gitMode() {
+			found, err = selectGitFiles(ctx, path, opts, filter)
+			if err != nil {
+				return "", nil, nil, fmt.Errorf("git selection: %v", err)
+			}
+		} else {
+			err = walkTree(path, opts.Symlinks, visit(ctx, &found, filter, path))
+			if err != nil {
+				logger.Warnf("file walk error: %s\n", err)
+				return "", nil, nil, fmt.Errorf("file walk: %v", err)
+			}
+		}
+
+		for _, file := range found {
+			if err := add(file, false); err != nil {
+				return "", nil, nil, err
+			}
+		}
+	}
+
+	sort.SliceStable(files, func(i, j int) bool {
+		return lessPath(files[i].Rel, files[j].Rel)
+	})
+
+	return base, files, filter.excluded, nil
+}
+
+// selectGoPackageFiles picks the files of the Go packages of the options
+// (and their dependencies) that the filter selects, keeping them in
+// dependency order rather than in walk order.
+func selectGoPackageFiles(ctx context.Context, opts *ClipOptions) (string, []selectedFile, []SkippedFile, error) {
+	candidates, err := resolveGoPackages(ctx, opts)
+	if err != nil {
+		return "", nil, nil, fmt.Errorf("go packages: %v", err)
+	}
+
+	base, err := commonBase(candidates)
+	if err != nil {
+		return "", nil, nil, err
+	}
+
+	filter, err := newFileFilter(base, opts)
+	if err != nil {
+		return "", nil, nil, fmt.Errorf("filter: %v", err)
+	}
+
+	files := make([]selectedFile, 0, len(candidates))
+	for _, candidate := range candidates {
+		selected, err := filter.selectsPath(candidate)
+		if err != nil {
+			return "", nil, nil, err
+		}
+		if !selected {
+			continue
+		}
+
+		rel, err := filter.relative(candidate)
+		if err != nil {
+			return "", nil, nil, err
+		}
+		files = append(files, selectedFile{Path: candidate, Rel: rel})
+	}
+
+	return base, files, filter.excluded, nil
+}
+
+// selectGitFiles narrows the files picked by git under the root
+// down to those that the filter selects, so both selections obey the same rules.
+// Symbolic links are handled by the Symlinks policy, as in a walk.
+func selectGitFiles(ctx context.Context, root string, opts *ClipOptions, filter *fileFilter) ([]string, error) {
+	candidates, err := gitSelection(root, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]string, 0, len(candidates))
+	for _, candidate := range candidates {
+		selected, err := filter.selectsPath(candidate)
+		if err != nil {
+			return nil, err
+		}
+		if !selected {
+			continue
+		}
+
+		info, err := os.Lstat(candidate)
+		if err == nil && isSymlink(info) {
+			switch symlinkPolicy(opts.Symlinks) {
+			case SymlinkSkip:
+				continue
+			case SymlinkFollow:
+				target, err := os.Stat(candidate)
+				if err != nil {
+					logger.Warnf("skipping broken symlink '%s': %v", candidate, err)
+					continue
+				}
+				if target.IsDir() {
+					err = walkTree(candidate, SymlinkFollow, visit(ctx, &files, filter, candidate))
+					if err != nil {
+						return nil, err
+					}
+					continue
+				}
+			}
+		}
+
+		files = append(files, candidate)
+	}
+
+	return files, nil
+}
+
+// readSymlink returns the target of the path when it is a
+// symbolic link or an empty string otherwise.
+func readSymlink(path string) (string, error) {
+	info, err := os.Lstat(path)
+	if err != nil || !isSymlink(info) {
+		return "", err
+	}
+	return os.Readlink(path)
+}
+
+// commonBase returns the deepest directory containing every path. A directory
+// counts as itself, while a file counts as the directory holding it.
+func commonBase(absPaths []string) (string, error) {
+	var base []string
+
+	for i, path := range absPaths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return "", err
+		}
+		dir := path
+		if !info.IsDir() {
+			dir = filepath.Dir(path)
+		}
+
+		segments := strings.Split(filepath.ToSlash(dir), "/")
+		if i == 0 {
+			base = segments
+			continue
+		}
+
+		n := 0
+		for n < len(base) && n < len(segments) && base[n] == segments[n] {
+			n++
+		}
+		base = base[:n]
+	}
+
+	if len(base) == 0 {
+		return "", fmt.Errorf("no paths")
+	}
+
+	joined := strings.Join(base, "/")
+	if joined == "" {
+		joined = "/" // Only the filesystem root is shared
+	}
+	return filepath.FromSlash(joined), nil
+}
+
+// lessPath orders slash separated paths the way a walk visits them,
+// comparing segment by segment, so "a/b" comes before "a-b".
+func lessPath(a, b string) bool {
+	as, bs := strings.Split(a, "/"), strings.Split(b, "/")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		if as[i] != bs[i] {
+			return as[i] < bs[i]
+		}
+	}
+	return len(as) < len(bs)
+}
+
+// displayPath returns the path relative to the working directory
+// when it is under it, otherwise the path itself.
+func displayPath(abs string) string {
+	wd, err := os.Getwd()
+	if err != nil {
+		return abs
+	}
+	rel, err := filepath.Rel(wd, abs)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return abs
+	}
+	return rel
+}
@@ -0,0 +1,130 @@
+package utils_common
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func Test_selectFiles_Multiple_Paths(t *testing.T) {
+	arrTestDir, cleanup := testCreateTestDir(t)
+	defer cleanup()
+
+	root := filepath.Join(arrTestDir...)
+
+	testCreateFilesAndFolders(t, arrTestDir, []fileDetail{
+		{Name: "cmd", Type: fileTypeFolder},
+		{Name: filepath.Join("cmd", "main.go"), Type: fileTypeFile},
+		{Name: "internal", Type: fileTypeFolder},
+		{Name: filepath.Join("internal", "a.go"), Type: fileTypeFile},
+		{Name: filepath.Join("internal", "b.go"), Type: fileTypeFile},
+		{Name: "internal-x", Type: fileTypeFolder},
+		{Name: filepath.Join("internal-x", "c.go"), Type: fileTypeFile},
+		{Name: "node_modules", Type: fileTypeFolder},
+		{Name: filepath.Join("node_modules", "lib.js"), Type: fileTypeFile},
+	})
+
+	base, files, _, err := selectFiles(context.Background(), &ClipOptions{
+		Paths: []string{
+			filepath.Join(root, "internal-x"),
+			filepath.Join(root, "internal"),
+			filepath.Join(root, "cmd", "main.go"),
+			filepath.Join(root, "internal", "a.go"),
+			filepath.Join(root, "node_modules", "lib.js"),
+		},
+		Exclusions: []string{"node_modules"},
+	})
+	require.NoError(t, err, "select files")
+
+	var rel []string
+	for _, file := range files {
+		rel = append(rel, file.Rel)
+	}
+
+	assert.Equal(t, root, base)
+	assert.Equal(t, []string{
+		"cmd/main.go",
+		"internal/a.go",
+		"internal/b.go",
+		"internal-x/c.go",
+		"node_modules/lib.js",
+	}, rel, "files named explicitly are kept and duplicates are dropped")
+}
+
+func Test_selectFiles_Skips_The_Output(t *testing.T) {
+	root := t.TempDir()
+	for _, name := range []string{"a.go", "bundle.txt"} {
+		require.NoError(t, os.WriteFile(filepath.Join(root, name), []byte("a\n"), 0644))
+	}
+
+	_, files, _, err := selectFiles(context.Background(), &ClipOptions{Paths: []string{root}, OutFile: filepath.Join(root, "bundle.txt")})
+	require.NoError(t, err)
+	require.Len(t, files, 1, "a previous output is not clipped again")
+	assert.Equal(t, "a.go", files[0].Rel)
+}
+
+func Test_selectFiles_Records_Why_Files_Are_Excluded(t *testing.T) {
+	root := t.TempDir()
+	for _, name := range []string{"a.go", "a.log", ".env", "bundle.txt", filepath.Join("vendor", "v.go"), filepath.Join("dist", "d.js")} {
+		require.NoError(t, os.MkdirAll(filepath.Join(root, filepath.Dir(name)), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(root, name), []byte("a\n"), 0644))
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(root, gitIgnoreFile), []byte(".env\ndist/\n"), 0644))
+
+	_, _, excluded, err := selectFiles(context.Background(), &ClipOptions{
+		Paths:      []string{root},
+		Exclusions: []string{"vendor"},
+		Exclude:    []string{"*.log"},
+		OutFile:    filepath.Join(root, "bundle.txt"),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []SkippedFile{
+		{Path: ".env", Reason: "ignore file"},
+		{Path: "a.log", Reason: "exclude glob '*.log'"},
+		{Path: "bundle.txt", Reason: "output of the clip"},
+		{Path: "dist/", Reason: "ignore file"},
+		{Path: "vendor/", Reason: "excluded prefix 'vendor'"},
+	}, excluded)
+}
+
+func Test_commonBase(t *testing.T) {
+	arrTestDir, cleanup := testCreateTestDir(t)
+	defer cleanup()
+
+	root := filepath.Join(arrTestDir...)
+
+	testCreateFilesAndFolders(t, arrTestDir, []fileDetail{
+		{Name: "a", Type: fileTypeFolder},
+		{Name: filepath.Join("a", "b"), Type: fileTypeFolder},
+		{Name: filepath.Join("a", "b", "x.go"), Type: fileTypeFile},
+		{Name: filepath.Join("a", "c"), Type: fileTypeFolder},
+	})
+
+	type testCase struct {
+		name  string
+		paths []string
+		base  string
+	}
+
+	testCases := []testCase{
+		{name: "Single Directory", paths: []string{"a/b"}, base: "a/b"},
+		{name: "Single File", paths: []string{"a/b/x.go"}, base: "a/b"},
+		{name: "Siblings", paths: []string{"a/b", "a/c"}, base: "a"},
+		{name: "File And Directory", paths: []string{"a/b/x.go", "a/c"}, base: "a"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var paths []string
+			for _, path := range tc.paths {
+				paths = append(paths, filepath.Join(root, filepath.FromSlash(path)))
+			}
+			base, err := commonBase(paths)
+			require.NoError(t, err, "common base")
+			assert.Equal(t, filepath.Join(root, filepath.FromSlash(tc.base)), base)
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package utils_common
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// ClipBundle puts the bundle onto the clipboard, recording every part into
+// the history when given. Bundles of several parts are copied one part at
+// a time, as the user presses enter.
+func ClipBundle(bundle *Bundle, history *History) error {
+	if err := clipChunks(bundle.Parts, history, os.Stdin, os.Stderr); err != nil {
+		logger.Warnf("Clipboard write error: %s\n", err)
+		return fmt.Errorf("clip: %v", err)
+	}
+	return nil
+}
+
+// WriteBundle writes every part of the bundle to w.
+func WriteBundle(bundle *Bundle, w io.Writer) error {
+	if _, err := io.WriteString(w, bundle.String()); err != nil {
+		return fmt.Errorf("write bundle: %v", err)
+	}
+	return nil
+}
+
+// WriteBundleToFile writes every part of the bundle to the file
+// at path, replacing its contents.
+func WriteBundleToFile(bundle *Bundle, path string) error {
+	if err := os.WriteFile(path, []byte(bundle.String()), 0644); err != nil {
+		return fmt.Errorf("write bundle to %s: %v", path, err)
+	}
+	return nil
+}
+
+// WriteBundleToDir writes every part of a split bundle to its own file in
+// the directory, created if missing, along with the index of the parts.
+// The bundles listed by the index of an earlier split are removed first.
+// A directory holding other files but no index is refused and so is
+// overwriting a file the earlier index did not list.
+func WriteBundleToDir(bundle *Bundle, dir string) error {
+	if len(bundle.Names) != len(bundle.Parts) || len(bundle.Names) == 0 {
+		return fmt.Errorf("the bundle is not split into named parts")
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("create %s: %v", dir, err)
+	}
+	if err := clearSplitDir(dir); err != nil {
+		return err
+	}
+	for i, part := range bundle.Parts {
+		path := filepath.Join(dir, bundle.Names[i])
+		if _, err := os.Lstat(path); err == nil {
+			return fmt.Errorf("write bundle to %s: the file exists and is not a bundle of the earlier split", path)
+		}
+		if err := os.WriteFile(path, []byte(part), 0644); err != nil {
+			return fmt.Errorf("write bundle to %s: %v", path, err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(dir, splitIndex), []byte(bundle.Index), 0644); err != nil {
+		return fmt.Errorf("write index: %v", err)
+	}
+	return nil
+}
+
+// clearSplitDir removes the bundles of an earlier split from the directory,
+// as listed by its index. A directory that is not empty must hold one.
+func clearSplitDir(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("read %s: %v", dir, err)
+	}
+	if len(entries) == 0 {
+		return nil
+	}
+
+	index, err := os.ReadFile(filepath.Join(dir, splitIndex))
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("read index: %v", err)
+	}
+	names, ok := splitIndexBundles(string(index))
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrOutDirNotEmpty, dir)
+	}
+
+	for _, name := range names {
+		if err := os.Remove(filepath.Join(dir, name)); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("remove earlier bundle: %v", err)
+		}
+	}
+	return nil
+}
+
+// clipChunks puts the chunks onto the clipboard one at a time,
+// waiting for the user to press enter before moving to the next one.
+func clipChunks(chunks []string, history *History, in io.Reader, out io.Writer) error {
+	reader := bufio.NewReader(in)
+
+	for i, chunk := range chunks {
+		if err := CopyToClipboard(chunk, history); err != nil {
+			return fmt.Errorf("clip part %d/%d: %v", i+1, len(chunks), err)
+		}
+
+		if len(chunks) == 1 {
+			return nil
+		}
+
+		_, _ = fmt.Fprintf(out, "Part \033[1;34m%d/%d\033[0m (~%d tokens) copied to clipboard.", i+1, len(chunks), EstimateTokens(chunk))
+		if i+1 == len(chunks) {
+			_, _ = fmt.Fprintln(out)
+			return nil
+		}
+
+		_, _ = fmt.Fprint(out, " Press enter to copy the next part...")
+		if _, err := reader.ReadString('\n'); err != nil {
+			_, _ = fmt.Fprintln(out)
+			return fmt.Errorf("waiting for part %d/%d: %v", i+2, len(chunks), err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,39 @@
+package utils_common
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteBundle(t *testing.T) {
+	bundle := &Bundle{Parts: []string{"[part 1/2]\na", "[part 2/2]\nb"}}
+
+	var buf bytes.Buffer
+	err := WriteBundle(bundle, &buf)
+	require.NoError(t, err)
+	require.Equal(t, "[part 1/2]\na[part 2/2]\nb", buf.String())
+}
+
+func TestWriteBundleToFile(t *testing.T) {
+	arrTestDir, cleanup := testCreateTestDir(t)
+	defer cleanup()
+
+	path := filepath.Join(append(arrTestDir, "bundle.txt")...)
+	bundle := &Bundle{Parts: []string{"\n\n--- a.go ---\n\npackage a"}}
+
+	err := WriteBundleToFile(bundle, path)
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, bundle.String(), string(data))
+}
+
+func TestWriteBundleToFile_Fail_Missing_Dir(t *testing.T) {
+	err := WriteBundleToFile(&Bundle{}, filepath.Join("missing", "dir", "bundle.txt"))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "write bundle to")
+}
@@ -0,0 +1,235 @@
+package utils_common
+
+import (
+	"errors"
+	"fmt"
+	"github.com/dembygenesis/local.tools/internal/lib/doc_generator"
+	"go/parser"
+	"go/token"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	// SplitByDir writes one bundle per directory.
+	SplitByDir = "dir"
+	// SplitByPackage writes one bundle per Go package, test packages apart.
+	SplitByPackage = "package"
+	// splitBySizePrefix writes bundles of at most a number of bytes, e.g "size:500k".
+	splitBySizePrefix = "size:"
+
+	// splitIndex is the name of the index written next to the split bundles.
+	splitIndex = "INDEX.md"
+	// splitIndexTitle opens the index, telling it apart from other INDEX.md files.
+	splitIndexTitle = "# Bundle index"
+)
+
+// ErrOutDirNotEmpty is returned for an output directory holding files that
+// were not written by an earlier split.
+var ErrOutDirNotEmpty = errors.New("the out dir is not empty and holds no bundle index")
+
+var (
+	unsafeNameChars   = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+	splitFileName     = regexp.MustCompile(`^[0-9]{3,}-[A-Za-z0-9._-]+$`)
+	splitNameReplacer = strings.NewReplacer("/", "-", " (", "-", ")", "")
+)
+
+// bundleSplit is one of the bundles a selection is split into.
+type bundleSplit struct {
+	Name  string // File name in the output directory
+	Group string // Directory, package or part the files belong to
+	Files []BundleFile
+}
+
+// parseSplitBy validates a --split-by value and returns the byte budget
+// of "size:<n>", where n takes an optional "k" or "m" suffix.
+func parseSplitBy(splitBy string) (int64, error) {
+	switch splitBy {
+	case SplitByDir, SplitByPackage:
+		return 0, nil
+	}
+
+	size, ok := strings.CutPrefix(splitBy, splitBySizePrefix)
+	if !ok {
+		return 0, fmt.Errorf("unknown split '%s', expected 'dir', 'package' or 'size:<bytes>'", splitBy)
+	}
+
+	multiplier := int64(1)
+	switch lower := strings.ToLower(size); {
+	case strings.HasSuffix(lower, "k"):
+		multiplier, size = 1024, size[:len(size)-1]
+	case strings.HasSuffix(lower, "m"):
+		multiplier, size = 1024*1024, size[:len(size)-1]
+	}
+	n, err := strconv.ParseInt(size, 10, 64)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("invalid split size '%s', expected a positive number of bytes (e.g 'size:500k')", splitBy)
+	}
+	return n * multiplier, nil
+}
+
+// splitExtension returns the file extension of the bundles of a format.
+func splitExtension(format string) string {
+	switch strings.ToLower(strings.TrimSpace(format)) {
+	case FormatMarkdown:
+		return ".md"
+	case FormatXML:
+		return ".xml"
+	case FormatJSON:
+		return ".json"
+	}
+	return ".txt"
+}
+
+// splitBundleFiles groups the files by directory, Go package or size, in the
+// order they were selected in. The sources are the selected files the bundle
+// files were read from, used to read the package clause of Go files.
+//
+// Split by size, every bundle holds as many files as fit in the budget along
+// with its marker and tree, as laid out in the format. Only a file over the
+// budget by itself gets a bundle exceeding it.
+func splitBundleFiles(base string, sources []selectedFile, files []BundleFile, formatter Formatter, splitBy string, format string) ([]bundleSplit, error) {
+	budget, err := parseSplitBy(splitBy)
+	if err != nil {
+		return nil, err
+	}
+
+	var splits []bundleSplit
+	if budget > 0 {
+		// The widest marker the bundles can have, so none outgrows its count.
+		marker := splitMarker(len(files), len(files), fmt.Sprintf("part %d", len(files)))
+		empty := len(formatter.FormatBundle(marker, "", nil))
+
+		var current []BundleFile
+		var currentBytes int // Bytes of the current files, without the marker and tree
+		for _, file := range files {
+			// Files are laid out one after the other, so they add up to
+			// their bytes as the first file and as any following one.
+			one := len(formatter.FormatBundle(marker, "", []BundleFile{file}))
+			firstBytes := one - empty
+			nextBytes := len(formatter.FormatBundle(marker, "", []BundleFile{file, file})) - one
+
+			if len(current) > 0 {
+				next := append(current[:len(current):len(current)], file)
+				size := len(formatter.FormatBundle(marker, bundleTree(base, next), nil)) + currentBytes + nextBytes
+				if int64(size) <= budget {
+					current, currentBytes = next, currentBytes+nextBytes
+					continue
+				}
+				splits = append(splits, bundleSplit{Files: current})
+			}
+
+			current, currentBytes = []BundleFile{file}, firstBytes
+			if size := len(formatter.FormatBundle(marker, bundleTree(base, current), nil)) + currentBytes; int64(size) > budget {
+				logger.Warnf("file '%s' alone exceeds the %s split size", file.Path, FormatBytes(budget))
+			}
+		}
+		if len(current) > 0 {
+			splits = append(splits, bundleSplit{Files: current})
+		}
+		for i := range splits {
+			splits[i].Group = fmt.Sprintf("part %d", i+1)
+		}
+	} else {
+		groups := make(map[string]int)
+		for i, file := range files {
+			group := path.Dir(file.Path)
+			if splitBy == SplitByPackage {
+				group = packageGroup(sources[i], group)
+			}
+			index, ok := groups[group]
+			if !ok {
+				index = len(splits)
+				groups[group] = index
+				splits = append(splits, bundleSplit{Group: group})
+			}
+			splits[index].Files = append(splits[index].Files, file)
+		}
+	}
+
+	width := len(strconv.Itoa(len(splits)))
+	ext := splitExtension(format)
+	for i := range splits {
+		label := "part"
+		if budget == 0 {
+			group := splits[i].Group
+			if group == "." || strings.HasPrefix(group, ". (") {
+				group = "root" + group[1:]
+			}
+			label = unsafeNameChars.ReplaceAllString(splitNameReplacer.Replace(group), "_")
+		}
+		splits[i].Name = fmt.Sprintf("%0*d-%s%s", max(width, 3), i+1, label, ext)
+	}
+
+	return splits, nil
+}
+
+// packageGroup names the Go package of a file by its directory, followed by
+// the package's name when it differs, e.g "internal/cli (cli_test)", so a
+// directory's external test package is split apart. Other files and Go
+// files that cannot be parsed are grouped by their directory.
+func packageGroup(source selectedFile, dir string) string {
+	if filepath.Ext(source.Path) != ".go" || source.Link != "" {
+		return dir
+	}
+	file, err := parser.ParseFile(token.NewFileSet(), source.Path, nil, parser.PackageClauseOnly)
+	if err != nil {
+		return dir
+	}
+	if file.Name.Name == filepath.Base(filepath.Dir(source.Path)) {
+		return dir
+	}
+	return fmt.Sprintf("%s (%s)", dir, file.Name.Name)
+}
+
+// splitMarker marks every split bundle within its format, naming its group.
+func splitMarker(part, total int, group string) string {
+	return fmt.Sprintf("bundle %d/%d: %s", part, total, group)
+}
+
+// splitIndexBundles returns the bundles listed by the index of an earlier
+// split. It returns false when the index was not written by a split.
+func splitIndexBundles(index string) ([]string, bool) {
+	if !strings.HasPrefix(index, splitIndexTitle+"\n") {
+		return nil, false
+	}
+	var names []string
+	for _, line := range strings.Split(index, "\n") {
+		if name, ok := strings.CutPrefix(line, "## "); ok && splitFileName.MatchString(name) {
+			names = append(names, name)
+		}
+	}
+	return names, true
+}
+
+// formatSplitIndex lists the split bundles with their group, size and
+// estimated tokens, followed by the files of every bundle, as markdown.
+func formatSplitIndex(base string, splitBy string, splits []bundleSplit, parts []string) string {
+	table := [][]string{{"Bundle", "Group", "Files", "Size", "Tokens"}}
+	for i, split := range splits {
+		table = append(table, []string{
+			split.Name,
+			split.Group,
+			formatCount(len(split.Files)),
+			FormatBytes(int64(len(parts[i]))),
+			formatCount(EstimateTokens(parts[i])),
+		})
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s\n\nFiles clipped from %s, split by %s into %d bundles.\n\n", splitIndexTitle, base, splitBy, len(splits))
+	sb.WriteString(doc_generator.FormatAsMDTable(table))
+	sb.WriteString("\n")
+
+	for _, split := range splits {
+		fmt.Fprintf(&sb, "\n## %s\n\n", split.Name)
+		for _, file := range split.Files {
+			fmt.Fprintf(&sb, "- %s\n", file.Path)
+		}
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,198 @@
+package utils_common
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func Test_parseSplitBy(t *testing.T) {
+	testCases := []struct {
+		splitBy  string
+		expected int64
+		fails    bool
+	}{
+		{splitBy: "dir"},
+		{splitBy: "package"},
+		{splitBy: "size:1000", expected: 1000},
+		{splitBy: "size:500k", expected: 500 * 1024},
+		{splitBy: "size:2M", expected: 2 * 1024 * 1024},
+		{splitBy: "size:0", fails: true},
+		{splitBy: "size:", fails: true},
+		{splitBy: "file", fails: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.splitBy, func(t *testing.T) {
+			budget, err := parseSplitBy(tc.splitBy)
+			if tc.fails {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, budget)
+		})
+	}
+}
+
+func TestBuildBundle_Split(t *testing.T) {
+	root := t.TempDir()
+	for name, content := range map[string]string{
+		"main.go":              "package main\n",
+		"readme.md":            "# Readme\n",
+		"cli/cli.go":           "package cli\n",
+		"cli/cli_test.go":      "package cli_test\n",
+		"cli/internal/util.go": "package internal\n",
+	} {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+
+	testCases := []struct {
+		splitBy  string
+		expected []string
+	}{
+		{
+			splitBy:  "dir",
+			expected: []string{"001-cli.md", "002-cli-internal.md", "003-root.md"},
+		},
+		{
+			splitBy:  "package",
+			expected: []string{"001-cli.md", "002-cli-cli_test.md", "003-cli-internal.md", "004-root-main.md", "005-root.md"},
+		},
+		{
+			splitBy:  "size:60",
+			expected: []string{"001-part.md", "002-part.md", "003-part.md", "004-part.md", "005-part.md"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.splitBy, func(t *testing.T) {
+			bundle, err := BuildBundle(context.Background(), &ClipOptions{Paths: []string{root}, Format: FormatMarkdown, SplitBy: tc.splitBy})
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.expected, bundle.Names)
+			require.Len(t, bundle.Parts, len(tc.expected))
+			assert.True(t, strings.HasPrefix(bundle.Parts[0], "<!-- bundle 1/"), "every bundle opens with its marker")
+			assert.Contains(t, bundle.Index, "| "+tc.expected[0])
+
+			out := t.TempDir()
+			require.NoError(t, WriteBundleToDir(bundle, out))
+			entries, err := os.ReadDir(out)
+			require.NoError(t, err)
+			assert.Len(t, entries, len(tc.expected)+1)
+			assert.FileExists(t, filepath.Join(out, splitIndex))
+		})
+	}
+}
+
+func TestWriteBundleToDir_Fail_Not_Split(t *testing.T) {
+	err := WriteBundleToDir(&Bundle{Parts: []string{"a"}}, t.TempDir())
+	require.Error(t, err)
+}
+
+func TestBuildBundle_Split_By_Size_Fits_The_Budget(t *testing.T) {
+	root := t.TempDir()
+	for i := 0; i < 40; i++ {
+		path := filepath.Join(root, fmt.Sprintf("dir%d", i%4), fmt.Sprintf("file%02d.go", i))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte("package x\n\n// "+strings.Repeat("word ", 10+i*3)+"\n"), 0644))
+	}
+	const budget = 1024
+
+	for _, format := range []string{FormatPlain, FormatMarkdown, FormatXML, FormatJSON} {
+		t.Run(format, func(t *testing.T) {
+			bundle, err := BuildBundle(context.Background(), &ClipOptions{Paths: []string{root}, Format: format, SplitBy: "size:1k", DryRun: true})
+			require.NoError(t, err)
+			require.Greater(t, len(bundle.Parts), 1)
+
+			var files []BundleFile
+			for i, part := range bundle.Parts {
+				assert.LessOrEqual(t, len(part), budget, "bundle %s is over the budget", bundle.Names[i])
+
+				parsed, err := ParseBundle(part)
+				require.NoError(t, err)
+				files = append(files, parsed...)
+			}
+			assert.Len(t, files, 40, "every file is in one of the bundles")
+		})
+	}
+}
+
+func TestBuildBundle_Split_Json_Bundles_Are_Documents(t *testing.T) {
+	root := t.TempDir()
+	for _, name := range []string{"a/a.go", "b/b.go"} {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte("package x\n"), 0644))
+	}
+
+	bundle, err := BuildBundle(context.Background(), &ClipOptions{Paths: []string{root}, Format: FormatJSON, SplitBy: SplitByDir, DryRun: true})
+	require.NoError(t, err)
+	require.Len(t, bundle.Parts, 2)
+
+	for i, part := range bundle.Parts {
+		var decoded jsonBundle
+		require.NoError(t, json.Unmarshal([]byte(part), &decoded), "bundle %d should be a single JSON document", i)
+		assert.Equal(t, fmt.Sprintf("bundle %d/2: %s", i+1, []string{"a", "b"}[i]), decoded.Part)
+		assert.NotEmpty(t, decoded.Tree)
+		require.Len(t, decoded.Files, 1)
+	}
+}
+
+func TestWriteBundleToDir_Removes_The_Earlier_Split(t *testing.T) {
+	out := t.TempDir()
+
+	first := &Bundle{Parts: []string{"a", "b"}, Names: []string{"001-a.md", "002-b.md"}, Index: splitIndexTitle + "\n\n## 001-a.md\n\n## 002-b.md\n"}
+	require.NoError(t, WriteBundleToDir(first, out))
+	require.NoError(t, os.WriteFile(filepath.Join(out, "003-notes.md"), []byte("mine"), 0644))
+
+	second := &Bundle{Parts: []string{"c"}, Names: []string{"001-c.md"}, Index: splitIndexTitle + "\n\n## 001-c.md\n"}
+	require.NoError(t, WriteBundleToDir(second, out))
+
+	entries, err := os.ReadDir(out)
+	require.NoError(t, err)
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	assert.ElementsMatch(t, []string{"001-c.md", "003-notes.md", splitIndex}, names, "only the listed bundles are removed")
+}
+
+func TestWriteBundleToDir_Fail_Not_Empty_Without_Index(t *testing.T) {
+	out := t.TempDir()
+	for name, content := range map[string]string{"001-intro.md": "intro", splitIndex: "# Docs\n"} {
+		require.NoError(t, os.WriteFile(filepath.Join(out, name), []byte(content), 0644))
+	}
+
+	bundle := &Bundle{Parts: []string{"new"}, Names: []string{"001-cli.md"}, Index: splitIndexTitle + "\n"}
+	require.ErrorIs(t, WriteBundleToDir(bundle, out), ErrOutDirNotEmpty)
+
+	intro, err := os.ReadFile(filepath.Join(out, "001-intro.md"))
+	require.NoError(t, err)
+	assert.Equal(t, "intro", string(intro))
+	index, err := os.ReadFile(filepath.Join(out, splitIndex))
+	require.NoError(t, err)
+	assert.Equal(t, "# Docs\n", string(index))
+}
+
+func TestBuildBundle_Split_Skips_The_Out_Dir(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(root, "a.go"), []byte("package a\n"), 0644))
+	out := filepath.Join(root, "bundles")
+
+	opts := ClipOptions{Paths: []string{root}, SplitBy: SplitByDir, OutDir: out}
+	for run := 0; run < 2; run++ {
+		bundle, err := BuildBundle(context.Background(), &opts)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"a.go"}, bundle.Result.Files, "run %d", run)
+		require.NoError(t, WriteBundleToDir(bundle, out))
+	}
+}
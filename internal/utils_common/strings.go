@@ -2,12 +2,8 @@ package utils_common
 
 import (
 	"encoding/json"
-	"fmt"
-	"github.com/atotto/clipboard"
-	"github.com/dembygenesis/local.tools/internal/common"
 	"github.com/google/uuid"
 	"github.com/pkg/errors"
-	"os"
 	"path/filepath"
 	"reflect"
 	"regexp"
@@ -50,66 +46,6 @@ func DecodeToStruct(in interface{}, out interface{}) error {
 	return nil
 }
 
-func visit(files *[]string, root string, ignoredPrefixed []string) filepath.WalkFunc {
-	return func(path string, info os.FileInfo, err error) error {
-
-		if err != nil {
-			fmt.Printf("Encountered an error accessing path %s: %s\n", path, err)
-			return err
-		}
-
-		relativePath, err := filepath.Rel(root, path)
-		if err != nil {
-			fmt.Printf("Error calculating relative path for %s: %s\n", path, err)
-			return err
-		}
-		for _, prefix := range ignoredPrefixed {
-			if strings.HasPrefix(strings.TrimSpace(relativePath), strings.TrimSpace(prefix)) {
-				if info.IsDir() {
-					return filepath.SkipDir // Skip the entire directory
-				}
-				return nil // Skip this file
-			}
-		}
-
-		*files = append(*files, path)
-
-		return nil
-	}
-}
-
-func CopyRootPathToClipboard(root string, exclude []string) ([]string, error) {
-	logger := common.GetLogger(nil)
-	var files []string
-
-	err := filepath.Walk(root, visit(&files, root, exclude))
-	if err != nil {
-		logger.Warnf("file walk error: %s\n", err)
-		return files, fmt.Errorf("file walk: %v", err)
-	}
-
-	var contentBuilder strings.Builder
-	for _, file := range files {
-		data, err := os.ReadFile(file)
-		if err != nil {
-			if !strings.Contains(err.Error(), "is a directory") {
-				logger.Warnf("reading file '%s' failed: %s\n", file, err)
-			}
-		}
-		fileContent := string(data)
-
-		contentBuilder.WriteString(fmt.Sprintf("\n\n--- %s ---\n\n", file))
-		contentBuilder.WriteString(fileContent)
-	}
-
-	if err := clipboard.WriteAll(contentBuilder.String()); err != nil {
-		logger.Warnf("Clipboard write error: %s\n", err)
-		return files, fmt.Errorf("clip: %v", err)
-	}
-
-	return files, nil
-}
-
 func IsValidFilename(filename string) error {
 	// Define constraints
 	const maxFilenameLength = 255
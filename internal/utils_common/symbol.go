@@ -0,0 +1,488 @@
+package utils_common
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultSymbolContext is the number of lines clipped around a call site.
+const DefaultSymbolContext = 3
+
+var ErrSymbolNotFound = errors.New("symbol not found")
+
+// lineRange is a 1-based, inclusive range of lines of a file.
+type lineRange struct {
+	Start int
+	End   int
+}
+
+// symbolSource is a Go file searched for a symbol.
+type symbolSource struct {
+	selected selectedFile
+	fset     *token.FileSet
+	lines    []string
+	file     *ast.File
+	dir      string
+	imports  map[string]string // Local name of an import, to the directory of its package in the module
+}
+
+// symbolSnippet is a range of a source clipped for a symbol.
+type symbolSnippet struct {
+	source int
+	lines  lineRange
+}
+
+// symbolTarget is the name searched for, split as "Recv.Name".
+type symbolTarget struct {
+	recv string // Receiver type or package name, empty for a plain name
+	name string
+}
+
+// loadSymbol reads the Go files among the selected files and returns the
+// snippets clipped for the Symbol of the options: its declarations, the
+// declarations of the module's types they use and the call sites referencing
+// it with SymbolContext lines around them. Snippets are named after their
+// file and lines, e.g "internal/cli/service.go:35-60".
+//
+// References are matched by name, without type checking, so calls to methods
+// of the same name on other types are clipped as well.
+func loadSymbol(ctx context.Context, files []selectedFile, opts *ClipOptions) ([]selectedFile, []loadedFile, error) {
+	sources, packageNames, err := parseSymbolSources(ctx, files)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	target := symbolTarget{name: opts.Symbol}
+	if i := strings.LastIndex(opts.Symbol, "."); i >= 0 {
+		target.recv, target.name = opts.Symbol[:i], opts.Symbol[i+1:]
+	}
+
+	declarations, nodes := findSymbolDeclarations(sources, target, packageNames)
+	if len(declarations) == 0 {
+		return nil, nil, fmt.Errorf("%w: '%s' is not declared in the %d Go files searched", ErrSymbolNotFound, opts.Symbol, len(sources))
+	}
+
+	types := symbolTypes(sources, declarations, nodes)
+	references := symbolReferences(sources, declarations, append(append([]symbolSnippet{}, declarations...), types...), target, packageNames, opts.SymbolContext)
+
+	snippets := make([]symbolSnippet, 0, len(declarations)+len(types)+len(references))
+	snippets = append(snippets, declarations...)
+	snippets = append(snippets, types...)
+	snippets = append(snippets, references...)
+
+	selected := make([]selectedFile, 0, len(snippets))
+	loaded := make([]loadedFile, 0, len(snippets))
+	for _, snippet := range snippets {
+		source := sources[snippet.source]
+		name := fmt.Sprintf("%s:%d-%d", source.selected.Rel, snippet.lines.Start, snippet.lines.End)
+		if snippet.lines.Start == snippet.lines.End {
+			name = fmt.Sprintf("%s:%d", source.selected.Rel, snippet.lines.Start)
+		}
+
+		file := loadedFile{
+			Content: strings.Join(source.lines[snippet.lines.Start-1:snippet.lines.End], "\n") + "\n",
+			First:   snippet.lines.Start,
+		}
+		if !opts.NoRedact {
+			file.Content, file.Redactions = redactSecrets(source.selected.Rel, file.Content)
+			for i := range file.Redactions {
+				file.Redactions[i].Line += snippet.lines.Start - 1
+			}
+		}
+		file.Size = int64(len(file.Content))
+
+		selected = append(selected, selectedFile{Path: source.selected.Path, Rel: name})
+		loaded = append(loaded, file)
+	}
+
+	return selected, loaded, nil
+}
+
+// parseSymbolSources parses the Go files among the selected files, along
+// with the imports that resolve to packages of their module and returns the
+// package name of every directory. Files that fail to parse are skipped with
+// a warning.
+func parseSymbolSources(ctx context.Context, files []selectedFile) ([]symbolSource, map[string]string, error) {
+	fset := token.NewFileSet()
+	modules := make(map[string]*goModule)
+	sources := make([]symbolSource, 0, len(files))
+
+	for _, selected := range files {
+		if err := ctx.Err(); err != nil {
+			return nil, nil, err
+		}
+		if filepath.Ext(selected.Path) != ".go" || selected.Link != "" {
+			continue
+		}
+
+		data, err := os.ReadFile(selected.Path)
+		if err != nil {
+			return nil, nil, err
+		}
+		file, err := parser.ParseFile(fset, selected.Path, data, parser.ParseComments)
+		if err != nil {
+			logger.Warnf("skipping '%s', it cannot be parsed: %v", selected.Rel, err)
+			continue
+		}
+
+		dir := filepath.Dir(selected.Path)
+		module, ok := modules[dir]
+		if !ok {
+			module, _ = findGoModule(dir) // Outside of a module, only the file's own package is resolved
+			modules[dir] = module
+		}
+
+		source := symbolSource{
+			selected: selected,
+			fset:     fset,
+			lines:    strings.Split(strings.TrimSuffix(string(data), "\n"), "\n"),
+			file:     file,
+			dir:      dir,
+			imports:  make(map[string]string),
+		}
+		for _, spec := range file.Imports {
+			importPath, err := strconv.Unquote(spec.Path.Value)
+			if err != nil || module == nil {
+				continue
+			}
+			importDir, ok := module.importDir(importPath)
+			if !ok {
+				continue
+			}
+			name := path.Base(importPath)
+			if spec.Name != nil {
+				name = spec.Name.Name
+			}
+			source.imports[name] = importDir
+		}
+		sources = append(sources, source)
+	}
+
+	// Imports named after their path are renamed to the package's actual name.
+	packageNames := make(map[string]string)
+	for _, source := range sources {
+		packageNames[source.dir] = source.file.Name.Name
+	}
+	for _, source := range sources {
+		for _, spec := range source.file.Imports {
+			if spec.Name != nil {
+				continue
+			}
+			importPath, _ := strconv.Unquote(spec.Path.Value)
+			importDir, ok := source.imports[path.Base(importPath)]
+			if !ok {
+				continue
+			}
+			if name, ok := packageNames[importDir]; ok && name != path.Base(importPath) {
+				delete(source.imports, path.Base(importPath))
+				source.imports[name] = importDir
+			}
+		}
+	}
+
+	return sources, packageNames, nil
+}
+
+// findSymbolDeclarations returns the declarations of the target, along with
+// their nodes, so the types they use can be looked up.
+func findSymbolDeclarations(sources []symbolSource, target symbolTarget, packageNames map[string]string) ([]symbolSnippet, []ast.Node) {
+	var snippets []symbolSnippet
+	var nodes []ast.Node
+
+	for i, source := range sources {
+		inPackage := target.recv == "" || packageNames[source.dir] == target.recv
+		fset := source.fset
+
+		for _, decl := range source.file.Decls {
+			switch decl := decl.(type) {
+			case *ast.FuncDecl:
+				if decl.Name.Name != target.name {
+					continue
+				}
+				matches := decl.Recv == nil && inPackage
+				if decl.Recv != nil && target.recv != "" {
+					matches = receiverName(decl) == target.recv
+				}
+				if matches {
+					snippets = append(snippets, symbolSnippet{source: i, lines: nodeLines(fset, decl.Doc, decl)})
+					nodes = append(nodes, decl)
+				}
+			case *ast.GenDecl:
+				if !inPackage {
+					continue
+				}
+				for _, spec := range decl.Specs {
+					if !specDeclares(spec, target.name) {
+						continue
+					}
+					if len(decl.Specs) == 1 {
+						snippets = append(snippets, symbolSnippet{source: i, lines: nodeLines(fset, decl.Doc, decl)})
+					} else {
+						snippets = append(snippets, symbolSnippet{source: i, lines: nodeLines(fset, specDoc(spec), spec)})
+					}
+					nodes = append(nodes, spec)
+				}
+			}
+		}
+	}
+
+	return snippets, nodes
+}
+
+// symbolTypes returns the declarations of the module's types that the
+// declarations use, whether from their own package or an imported one.
+func symbolTypes(sources []symbolSource, declarations []symbolSnippet, nodes []ast.Node) []symbolSnippet {
+	// The types declared by every package, by name.
+	types := make(map[string]map[string][]symbolSnippet)
+	for i, source := range sources {
+		fset := source.fset
+		for _, decl := range source.file.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				typeSpec := spec.(*ast.TypeSpec)
+				lines := nodeLines(fset, typeSpec.Doc, typeSpec)
+				if len(gen.Specs) == 1 {
+					lines = nodeLines(fset, gen.Doc, gen)
+				}
+				if types[source.dir] == nil {
+					types[source.dir] = make(map[string][]symbolSnippet)
+				}
+				types[source.dir][typeSpec.Name.Name] = append(types[source.dir][typeSpec.Name.Name], symbolSnippet{source: i, lines: lines})
+			}
+		}
+	}
+
+	var used []symbolSnippet
+	seen := make(map[symbolSnippet]bool)
+	for _, declaration := range declarations {
+		seen[declaration] = true
+	}
+	add := func(dir, name string) {
+		for _, snippet := range types[dir][name] {
+			if !seen[snippet] {
+				seen[snippet] = true
+				used = append(used, snippet)
+			}
+		}
+	}
+
+	for i, node := range nodes {
+		source := sources[declarations[i].source]
+		var inspect func(n ast.Node) bool
+		inspect = func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.SelectorExpr:
+				if pkg, ok := n.X.(*ast.Ident); ok {
+					if dir, ok := source.imports[pkg.Name]; ok {
+						add(dir, n.Sel.Name)
+						return false
+					}
+				}
+				ast.Inspect(n.X, inspect) // A selected field or method is never a type
+				return false
+			case *ast.Ident:
+				add(source.dir, n.Name)
+			}
+			return true
+		}
+		ast.Inspect(node, inspect)
+	}
+
+	sort.SliceStable(used, func(i, j int) bool {
+		if used[i].source != used[j].source {
+			return used[i].source < used[j].source
+		}
+		return used[i].lines.Start < used[j].lines.Start
+	})
+	return used
+}
+
+// symbolReferences returns the call sites of the target, with context lines
+// around them, merged when they overlap. The lines of the covered snippets
+// (the declarations and types) are left out, as they are clipped whole.
+func symbolReferences(sources []symbolSource, declarations []symbolSnippet, covered []symbolSnippet, target symbolTarget, packageNames map[string]string, context int) []symbolSnippet {
+	method := target.recv != ""
+	declared := make(map[string]bool)
+	for _, declaration := range declarations {
+		if dir := sources[declaration.source].dir; packageNames[dir] == target.recv {
+			method = false // "pkg.Name" rather than "Type.Method"
+		}
+		declared[sources[declaration.source].dir] = true
+	}
+
+	var snippets []symbolSnippet
+	for i, source := range sources {
+		fset := source.fset
+		var lines []int
+
+		var inspect func(n ast.Node) bool
+		inspect = func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.SelectorExpr:
+				if n.Sel.Name == target.name {
+					if method {
+						lines = append(lines, fset.Position(n.Sel.Pos()).Line)
+					} else if pkg, ok := n.X.(*ast.Ident); ok && declared[source.imports[pkg.Name]] {
+						lines = append(lines, fset.Position(n.Sel.Pos()).Line)
+						return false
+					}
+				}
+				ast.Inspect(n.X, inspect) // The selected name is a field or a method, never the symbol itself
+				return false
+			case *ast.KeyValueExpr:
+				if _, ok := n.Key.(*ast.Ident); ok {
+					ast.Inspect(n.Value, inspect)
+					return false
+				}
+			case *ast.Ident:
+				if !method && n.Name == target.name && declared[source.dir] {
+					lines = append(lines, fset.Position(n.Pos()).Line)
+				}
+			}
+			return true
+		}
+		ast.Inspect(source.file, inspect)
+
+		var ranges []lineRange
+		for _, line := range lines {
+			if withinSnippets(covered, i, line) {
+				continue
+			}
+			ranges = append(ranges, lineRange{
+				Start: max(line-context, 1),
+				End:   min(line+context, len(source.lines)),
+			})
+		}
+		for _, merged := range mergeRanges(ranges) {
+			for _, r := range subtractSnippets(merged, covered, i) {
+				snippets = append(snippets, symbolSnippet{source: i, lines: r})
+			}
+		}
+	}
+
+	return snippets
+}
+
+// receiverName returns the type name of the method's receiver,
+// without its pointer or type parameters.
+func receiverName(decl *ast.FuncDecl) string {
+	if decl.Recv == nil || len(decl.Recv.List) == 0 {
+		return ""
+	}
+	expr := decl.Recv.List[0].Type
+	for {
+		switch t := expr.(type) {
+		case *ast.StarExpr:
+			expr = t.X
+		case *ast.IndexExpr:
+			expr = t.X
+		case *ast.IndexListExpr:
+			expr = t.X
+		case *ast.Ident:
+			return t.Name
+		default:
+			return ""
+		}
+	}
+}
+
+// specDeclares tells whether the type, constant or variable spec declares the name.
+func specDeclares(spec ast.Spec, name string) bool {
+	switch spec := spec.(type) {
+	case *ast.TypeSpec:
+		return spec.Name.Name == name
+	case *ast.ValueSpec:
+		for _, ident := range spec.Names {
+			if ident.Name == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// specDoc returns the doc comment of a spec of a grouped declaration.
+func specDoc(spec ast.Spec) *ast.CommentGroup {
+	switch spec := spec.(type) {
+	case *ast.TypeSpec:
+		return spec.Doc
+	case *ast.ValueSpec:
+		return spec.Doc
+	}
+	return nil
+}
+
+// nodeLines returns the lines of the node, starting at its doc comment.
+func nodeLines(fset *token.FileSet, doc *ast.CommentGroup, node ast.Node) lineRange {
+	start := node.Pos()
+	if doc != nil {
+		start = doc.Pos()
+	}
+	return lineRange{Start: fset.Position(start).Line, End: fset.Position(node.End()).Line}
+}
+
+// withinSnippets tells whether the line of the source is clipped by one of the snippets.
+func withinSnippets(snippets []symbolSnippet, source int, line int) bool {
+	for _, snippet := range snippets {
+		if snippet.source == source && line >= snippet.lines.Start && line <= snippet.lines.End {
+			return true
+		}
+	}
+	return false
+}
+
+// subtractSnippets returns what remains of the range of the source once the
+// lines of the snippets are taken out of it.
+func subtractSnippets(r lineRange, snippets []symbolSnippet, source int) []lineRange {
+	remaining := []lineRange{r}
+	for _, snippet := range snippets {
+		if snippet.source != source {
+			continue
+		}
+		var next []lineRange
+		for _, rest := range remaining {
+			if snippet.lines.End < rest.Start || snippet.lines.Start > rest.End {
+				next = append(next, rest)
+				continue
+			}
+			if rest.Start < snippet.lines.Start {
+				next = append(next, lineRange{Start: rest.Start, End: snippet.lines.Start - 1})
+			}
+			if rest.End > snippet.lines.End {
+				next = append(next, lineRange{Start: snippet.lines.End + 1, End: rest.End})
+			}
+		}
+		remaining = next
+	}
+	return remaining
+}
+
+// mergeRanges sorts the ranges and joins those that overlap or touch.
+func mergeRanges(ranges []lineRange) []lineRange {
+	sort.Slice(ranges, func(i, j int) bool {
+		return ranges[i].Start < ranges[j].Start
+	})
+
+	var merged []lineRange
+	for _, r := range ranges {
+		if n := len(merged); n > 0 && r.Start <= merged[n-1].End+1 {
+			merged[n-1].End = max(merged[n-1].End, r.End)
+			continue
+		}
+		merged = append(merged, r)
+	}
+	return merged
+}
@@ -0,0 +1,131 @@
+package utils_common
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// testCreateSymbolModule writes a module where "store.Paginate" uses a type of
+// its own package and one of "models" and is called from "app".
+func testCreateSymbolModule(t *testing.T) string {
+	root := t.TempDir()
+	files := map[string]string{
+		"go.mod": "module example.com/m\n\ngo 1.21\n",
+		"models/models.go": "package models\n\n" +
+			"// Options are the paging options.\n" +
+			"type Options struct {\n\tSize int\n}\n\n" +
+			"type Unused struct{}\n",
+		"store/store.go": "package store\n\n" +
+			"import \"example.com/m/models\"\n\n" +
+			"type Page struct {\n\tItems []int\n}\n\n" +
+			"// Paginate splits the items.\n" +
+			"func Paginate(items []int, opts models.Options) Page {\n" +
+			"\treturn Page{Items: items[:opts.Size]}\n" +
+			"}\n\n" +
+			"type Service struct{}\n\n" +
+			"func (s *Service) Run() Page {\n" +
+			"\treturn Paginate(nil, models.Options{})\n" +
+			"}\n",
+		"app/app.go": "package app\n\n" +
+			"import (\n\tdb \"example.com/m/store\"\n)\n\n" +
+			"func main() {\n" +
+			"\tpage := db.Paginate([]int{1, 2}, models.Options{Size: 1})\n" +
+			"\t_ = page\n" +
+			"\ts := db.Service{}\n" +
+			"\ts.Run()\n" +
+			"}\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+	return root
+}
+
+func Test_loadSymbol(t *testing.T) {
+	root := testCreateSymbolModule(t)
+
+	testCases := []struct {
+		name     string
+		symbol   string
+		context  int
+		expected []string
+	}{
+		{
+			name:    "Function",
+			symbol:  "Paginate",
+			context: 1,
+			expected: []string{
+				"store/store.go:9-12",
+				"models/models.go:3-6",
+				"store/store.go:5-7",
+				"app/app.go:7-9",
+				"store/store.go:16-18",
+			},
+		},
+		{
+			name:     "Qualified_By_Package",
+			symbol:   "store.Paginate",
+			expected: []string{"store/store.go:9-12", "models/models.go:3-6", "store/store.go:5-7", "app/app.go:8", "store/store.go:17"},
+		},
+		{
+			name:     "Method",
+			symbol:   "Service.Run",
+			expected: []string{"store/store.go:16-18", "models/models.go:3-6", "store/store.go:5-7", "store/store.go:14", "app/app.go:11"},
+		},
+		{
+			name:     "Type",
+			symbol:   "Options",
+			expected: []string{"models/models.go:3-6", "store/store.go:10", "store/store.go:17"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			opts := ClipOptions{Paths: []string{root}, Symbol: tc.symbol, SymbolContext: tc.context}
+
+			_, files, _, err := selectFiles(context.Background(), &opts)
+			require.NoError(t, err)
+
+			snippets, loaded, err := loadSymbol(context.Background(), files, &opts)
+			require.NoError(t, err)
+			require.Len(t, loaded, len(snippets))
+
+			names := make([]string, 0, len(snippets))
+			for _, snippet := range snippets {
+				names = append(names, snippet.Rel)
+			}
+			assert.Equal(t, tc.expected, names)
+		})
+	}
+}
+
+func Test_loadSymbol_Fail_Not_Found(t *testing.T) {
+	root := testCreateSymbolModule(t)
+	opts := ClipOptions{Paths: []string{root}, Symbol: "Missing"}
+
+	_, files, _, err := selectFiles(context.Background(), &opts)
+	require.NoError(t, err)
+
+	_, _, err = loadSymbol(context.Background(), files, &opts)
+	require.ErrorIs(t, err, ErrSymbolNotFound)
+}
+
+func TestBuildBundle_Symbol_Numbers_Lines_From_The_File(t *testing.T) {
+	root := testCreateSymbolModule(t)
+
+	bundle, err := BuildBundle(context.Background(), &ClipOptions{Paths: []string{filepath.Join(root, "models")}, Symbol: "Options", LineNumbers: true})
+	require.NoError(t, err)
+
+	assert.Equal(t, "\n\n--- models.go:3-6 ---\n\n3 | // Options are the paging options.\n4 | type Options struct {\n5 | \tSize int\n6 | }\n", bundle.String())
+}
+
+func Test_mergeRanges(t *testing.T) {
+	assert.Equal(t, []lineRange{{1, 6}, {9, 10}}, mergeRanges([]lineRange{{4, 6}, {9, 10}, {1, 3}}))
+	assert.Equal(t, []lineRange{{1, 2}, {5, 6}}, subtractSnippets(lineRange{1, 6}, []symbolSnippet{{lines: lineRange{3, 4}}}, 0))
+}
@@ -0,0 +1,139 @@
+package utils_common
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+const (
+	// SymlinkSkip leaves symbolic links out entirely.
+	SymlinkSkip = "skip"
+	// SymlinkFollow treats symbolic links as their targets,
+	// descending into linked directories.
+	SymlinkFollow = "follow"
+	// SymlinkPreserve keeps symbolic links as links, without
+	// reading or copying their targets.
+	SymlinkPreserve = "preserve"
+)
+
+// symlinkPolicy returns the policy, defaulting to SymlinkFollow.
+func symlinkPolicy(policy string) string {
+	if policy == "" {
+		return SymlinkFollow
+	}
+	return policy
+}
+
+// walkTree walks the tree rooted at root like filepath.Walk does, calling fn
+// for every file and directory in lexical order, with the symbolic links
+// below the root handled by the policy:
+//   - SymlinkSkip never passes them to fn.
+//   - SymlinkFollow passes the targets' info under the links' paths and
+//     descends into linked directories, unless the directory is one of its own
+//     ancestors (the same file per os.SameFile, i.e the same device and inode
+//     on Unix), which would loop forever. Broken links are skipped.
+//   - SymlinkPreserve passes the links' own info, so they are never descended.
+//
+// The root itself is always followed, as it was asked for explicitly.
+func walkTree(root string, policy string, fn filepath.WalkFunc) error {
+	info, err := os.Stat(root)
+	if err != nil {
+		err = fn(root, nil, err)
+	} else {
+		err = walkPath(root, info, symlinkPolicy(policy), nil, fn)
+	}
+	if err == filepath.SkipDir {
+		return nil
+	}
+	return err
+}
+
+// walkPath walks path, whose ancestors are the directories above it.
+func walkPath(path string, info os.FileInfo, policy string, ancestors []os.FileInfo, fn filepath.WalkFunc) error {
+	if !info.IsDir() {
+		return fn(path, info, nil)
+	}
+
+	entries, readErr := os.ReadDir(path)
+	err := fn(path, info, readErr)
+	// A failed read is passed to fn once and the directory skipped when
+	// fn does not return the error, the same as filepath.Walk.
+	if readErr != nil || err != nil {
+		return err
+	}
+
+	ancestors = append(ancestors, info)
+	for _, entry := range entries {
+		entryPath := filepath.Join(path, entry.Name())
+
+		childInfo, err := entryInfo(entryPath, policy, ancestors)
+		if err != nil {
+			if err := fn(entryPath, nil, err); err != nil && err != filepath.SkipDir {
+				return err
+			}
+			continue
+		}
+		if childInfo == nil {
+			continue // Left out by the policy
+		}
+
+		err = walkPath(entryPath, childInfo, policy, ancestors, fn)
+		if err == filepath.SkipDir {
+			if childInfo.IsDir() {
+				continue
+			}
+			return nil // Skips the remaining files of the directory
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// entryInfo returns the info a directory entry is walked with,
+// or nil when the policy leaves it out.
+func entryInfo(path string, policy string, ancestors []os.FileInfo) (os.FileInfo, error) {
+	info, err := os.Lstat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !isSymlink(info) {
+		return info, nil
+	}
+
+	switch policy {
+	case SymlinkSkip:
+		return nil, nil
+	case SymlinkPreserve:
+		return info, nil
+	}
+
+	target, err := os.Stat(path)
+	if err != nil {
+		logger.Warnf("skipping broken symlink '%s': %v", path, err)
+		return nil, nil
+	}
+	if target.IsDir() {
+		for _, ancestor := range ancestors {
+			if os.SameFile(ancestor, target) {
+				logger.Warnf("skipping symlink '%s', it loops back to one of its parent directories", path)
+				return nil, nil
+			}
+		}
+	}
+
+	return target, nil
+}
+
+// isSymlink tells whether the info is the one of a symbolic link.
+func isSymlink(info os.FileInfo) bool {
+	return info.Mode()&os.ModeSymlink != 0
+}
+
+// symlinkPlaceholder stands in for the contents of a preserved symbolic link.
+func symlinkPlaceholder(target string) string {
+	return fmt.Sprintf("[symlink to %s]\n", target)
+}
@@ -0,0 +1,129 @@
+package utils_common
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// testCreateSymlinkTree creates a tree with a link to a file, a link to a
+// sibling directory, a link looping back to the root and a broken link.
+func testCreateSymlinkTree(t *testing.T) string {
+	t.Helper()
+
+	arrTestDir, cleanup := testCreateTestDir(t)
+	t.Cleanup(cleanup)
+
+	root := filepath.Join(arrTestDir...)
+	testCreateFilesAndFolders(t, arrTestDir, []fileDetail{
+		{Name: "lib", Type: fileTypeFolder},
+		{Name: filepath.Join("lib", "util.go"), Type: fileTypeFile},
+		{Name: "main.go", Type: fileTypeFile},
+	})
+
+	links := map[string]string{
+		"alias.go":                       "main.go",
+		"vendored":                       "lib",
+		filepath.Join("lib", "loop"):     "..",
+		filepath.Join("lib", "dangling"): "missing.go",
+	}
+	for name, target := range links {
+		require.NoError(t, os.Symlink(target, filepath.Join(root, name)), "symlink %s", name)
+	}
+
+	return root
+}
+
+func testWalkTree(t *testing.T, root string, policy string) []string {
+	t.Helper()
+
+	var paths []string
+	err := walkTree(root, policy, func(path string, info os.FileInfo, err error) error {
+		require.NoError(t, err)
+		if path != root {
+			rel, err := filepath.Rel(root, path)
+			require.NoError(t, err)
+			paths = append(paths, filepath.ToSlash(rel))
+		}
+		return nil
+	})
+	require.NoError(t, err)
+
+	return paths
+}
+
+func Test_walkTree(t *testing.T) {
+	root := testCreateSymlinkTree(t)
+
+	t.Run("Skip", func(t *testing.T) {
+		assert.Equal(t, []string{"lib", "lib/util.go", "main.go"}, testWalkTree(t, root, SymlinkSkip))
+	})
+
+	t.Run("Follow Breaks Loops", func(t *testing.T) {
+		assert.Equal(t, []string{
+			"alias.go",
+			"lib",
+			"lib/util.go",
+			"main.go",
+			"vendored",
+			"vendored/util.go",
+		}, testWalkTree(t, root, SymlinkFollow))
+	})
+
+	t.Run("Preserve", func(t *testing.T) {
+		assert.Equal(t, []string{
+			"alias.go",
+			"lib",
+			"lib/dangling",
+			"lib/loop",
+			"lib/util.go",
+			"main.go",
+			"vendored",
+		}, testWalkTree(t, root, SymlinkPreserve))
+	})
+}
+
+func Test_copyDir_Symlinks(t *testing.T) {
+	root := testCreateSymlinkTree(t)
+
+	t.Run("Follow", func(t *testing.T) {
+		dst := filepath.Join(t.TempDir(), "out")
+		_, err := copyDir(root, dst, nil, SymlinkFollow)
+		require.NoError(t, err)
+
+		info, err := os.Lstat(filepath.Join(dst, "vendored", "util.go"))
+		require.NoError(t, err)
+		assert.True(t, info.Mode().IsRegular(), "linked directories are copied")
+
+		_, err = os.Lstat(filepath.Join(dst, "lib", "loop"))
+		assert.True(t, os.IsNotExist(err), "loops are skipped")
+	})
+
+	t.Run("Preserve", func(t *testing.T) {
+		dst := filepath.Join(t.TempDir(), "out")
+		_, err := copyDir(root, dst, nil, SymlinkPreserve)
+		require.NoError(t, err)
+
+		for name, target := range map[string]string{"vendored": "lib", "lib/loop": ".."} {
+			got, err := os.Readlink(filepath.Join(dst, filepath.FromSlash(name)))
+			require.NoError(t, err, "%s is a link", name)
+			assert.Equal(t, target, got)
+		}
+	})
+}
+
+func TestBuildBundle_Preserved_Symlink(t *testing.T) {
+	root := testCreateSymlinkTree(t)
+
+	bundle, err := BuildBundle(context.Background(), &ClipOptions{
+		Paths:    []string{root},
+		Include:  []string{"alias.go"},
+		Symlinks: SymlinkPreserve,
+	})
+	require.NoError(t, err)
+
+	assert.Contains(t, bundle.String(), symlinkPlaceholder("main.go"))
+}
@@ -0,0 +1,45 @@
+package utils_common
+
+import (
+	"unicode"
+)
+
+// charsPerToken is the average number of characters that
+// BPE tokenizers fold into a single token for identifiers and prose.
+const charsPerToken = 4
+
+// EstimateTokens approximates the number of LLM tokens in s, offline.
+//
+// Letter and digit runs cost one token per charsPerToken characters,
+// every other symbol costs a token and whitespace is mostly folded
+// into its neighbours, except for line breaks. The estimate errs on
+// the high side for code, which keeps budgets safe.
+func EstimateTokens(s string) int {
+	tokens := 0
+	wordLen := 0
+
+	flushWord := func() {
+		if wordLen > 0 {
+			tokens += (wordLen + charsPerToken - 1) / charsPerToken
+			wordLen = 0
+		}
+	}
+
+	for _, r := range s {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_':
+			wordLen++
+		case r == '\n':
+			flushWord()
+			tokens++
+		case unicode.IsSpace(r):
+			flushWord()
+		default:
+			flushWord()
+			tokens++
+		}
+	}
+	flushWord()
+
+	return tokens
+}
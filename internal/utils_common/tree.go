@@ -0,0 +1,75 @@
+package utils_common
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+)
+
+// treeNode is a directory or a file of a rendered tree.
+type treeNode struct {
+	name     string
+	size     int64
+	isFile   bool
+	children map[string]*treeNode
+}
+
+// TreeEntry is a selected file and its size in bytes.
+type TreeEntry struct {
+	Path string
+	Size int64
+}
+
+// RenderTree draws an ASCII tree of the entries, whose paths are slash
+// separated and relative to the root. Each file is annotated with its size.
+func RenderTree(root string, entries []TreeEntry) string {
+	top := &treeNode{name: root, children: make(map[string]*treeNode)}
+
+	for _, entry := range entries {
+		node := top
+		segments := strings.Split(path.Clean(entry.Path), "/")
+		for i, segment := range segments {
+			child, ok := node.children[segment]
+			if !ok {
+				child = &treeNode{name: segment, children: make(map[string]*treeNode)}
+				node.children[segment] = child
+			}
+			if i == len(segments)-1 {
+				child.isFile = true
+				child.size = entry.Size
+			}
+			node = child
+		}
+	}
+
+	var sb strings.Builder
+	sb.WriteString(top.name + "\n")
+	writeTreeChildren(&sb, top, "")
+
+	return sb.String()
+}
+
+func writeTreeChildren(sb *strings.Builder, node *treeNode, prefix string) {
+	names := make([]string, 0, len(node.children))
+	for name := range node.children {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for i, name := range names {
+		child := node.children[name]
+		connector, indent := "├── ", "│   "
+		if i == len(names)-1 {
+			connector, indent = "└── ", "    "
+		}
+
+		if child.isFile {
+			sb.WriteString(fmt.Sprintf("%s%s%s (%s)\n", prefix, connector, child.name, FormatBytes(child.size)))
+			continue
+		}
+
+		sb.WriteString(prefix + connector + child.name + "/\n")
+		writeTreeChildren(sb, child, prefix+indent)
+	}
+}
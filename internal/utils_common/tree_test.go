@@ -0,0 +1,42 @@
+package utils_common
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestRenderTree(t *testing.T) {
+	entries := []TreeEntry{
+		{Path: "internal/cli/service.go", Size: 2048},
+		{Path: "main.go", Size: 120},
+		{Path: "internal/cli/clifakes/fake.go", Size: 3 * 1024},
+		{Path: "internal/cli/adapters.go", Size: 1536},
+	}
+
+	expected := "module\n" +
+		"├── internal/\n" +
+		"│   └── cli/\n" +
+		"│       ├── adapters.go (1.5 KB)\n" +
+		"│       ├── clifakes/\n" +
+		"│       │   └── fake.go (3 KB)\n" +
+		"│       └── service.go (2 KB)\n" +
+		"└── main.go (120 B)\n"
+
+	assert.Equal(t, expected, RenderTree("module", entries))
+}
+
+func TestRenderTree_Empty(t *testing.T) {
+	assert.Equal(t, ".\n", RenderTree(".", nil))
+}
+
+func Test_chunkFiles_Tree_Opens_First_Part(t *testing.T) {
+	files := []BundleFile{
+		{Path: "a", Content: "word word word word word"},
+		{Path: "b", Content: "word word word word word"},
+	}
+
+	chunks := chunkFiles(files, &plainFormatter{}, 20, "tree\n")
+	assert.Len(t, chunks, 2)
+	assert.Equal(t, "[part 1/2]\ntree\n"+(&plainFormatter{}).Format(files[:1]), chunks[0])
+	assert.NotContains(t, chunks[1], "tree")
+}
@@ -0,0 +1,200 @@
+package utils_common
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// largestContributors is the number of files reported as the
+// biggest contributors to a bundle.
+const largestContributors = 5
+
+// TruncatedFile is a file whose contents were cut down to a size limit.
+type TruncatedFile struct {
+	Path string `json:"path"`
+	Size int64  `json:"size"`
+	Kept int64  `json:"kept"`
+}
+
+// FileContribution is the number of bytes a file contributed to a bundle.
+type FileContribution struct {
+	Path  string `json:"path"`
+	Bytes int64  `json:"bytes"`
+}
+
+// truncateContent shortens the content to about maxBytes, keeping its head
+// and its tail on line boundaries, with a marker telling how many lines were
+// elided in between. Content made of a few very long lines (e.g minified
+// code) is cut on rune boundaries instead and the marker counts bytes.
+//
+// It returns the new content and the number of bytes kept from the original.
+func truncateContent(content string, maxBytes int64) (string, int, bool) {
+	if maxBytes < 0 || int64(len(content)) <= maxBytes {
+		return content, len(content), false
+	}
+
+	half := int(maxBytes / 2)
+	lines := strings.SplitAfter(content, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	headLen, head := 0, 0
+	for head < len(lines) && headLen+len(lines[head]) <= half {
+		headLen += len(lines[head])
+		head++
+	}
+
+	tailLen, tail := 0, len(lines)
+	for tail > head && tailLen+len(lines[tail-1]) <= half {
+		tailLen += len(lines[tail-1])
+		tail--
+	}
+
+	if head > 0 || tail < len(lines) || half == 0 {
+		marker := elisionMarker(tail-head, "lines")
+		return content[:headLen] + marker + content[len(content)-tailLen:], headLen + tailLen, true
+	}
+
+	// No whole line fits, so the content is cut mid-line.
+	headEnd := runeBoundary(content, half)
+	tailStart := len(content) - half
+	for tailStart < len(content) && !utf8.RuneStart(content[tailStart]) {
+		tailStart++
+	}
+	if tailStart < headEnd {
+		tailStart = headEnd
+	}
+	marker := "\n" + elisionMarker(tailStart-headEnd, "bytes")
+	return content[:headEnd] + marker + content[tailStart:], headEnd + len(content) - tailStart, true
+}
+
+// elisionMarker is the line standing in for the elided content.
+func elisionMarker(n int, unit string) string {
+	return fmt.Sprintf("[... %s %s elided ...]\n", formatCount(n), unit)
+}
+
+// runeBoundary moves i back to the start of the rune it falls in.
+func runeBoundary(s string, i int) int {
+	if i <= 0 {
+		return 0
+	}
+	if i >= len(s) {
+		return len(s)
+	}
+	for i > 0 && !utf8.RuneStart(s[i]) {
+		i--
+	}
+	return i
+}
+
+// formatCount renders n with thousands separators (e.g "4,210").
+func formatCount(n int) string {
+	if n < 0 {
+		return "-" + formatCount(-n)
+	}
+	digits := strconv.Itoa(n)
+
+	var sb strings.Builder
+	for i, d := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			sb.WriteByte(',')
+		}
+		sb.WriteRune(d)
+	}
+	return sb.String()
+}
+
+// fairShare returns the largest per-file size such that the files, each cut
+// down to it, fit in total bytes. Small files are kept whole, while the
+// remaining budget is split evenly between the larger ones. A negative
+// result means no cut is needed.
+func fairShare(sizes []int64, total int64) int64 {
+	sorted := append([]int64(nil), sizes...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	remaining := total
+	for i, size := range sorted {
+		share := remaining / int64(len(sorted)-i)
+		if size > share {
+			return share
+		}
+		remaining -= size
+	}
+
+	return -1
+}
+
+// applySizeLimits truncates the text files over the per-file limit and then
+// the largest ones until the files fit the total limit. A zero limit is
+// no limit. Binary placeholders count toward the total, but are never truncated.
+func applySizeLimits(files []BundleFile, binary []bool, maxFileBytes int64, maxTotalBytes int64) []TruncatedFile {
+	truncated := make([]TruncatedFile, 0)
+	if maxFileBytes <= 0 && maxTotalBytes <= 0 {
+		return truncated
+	}
+
+	fileLimit := int64(-1)
+	if maxFileBytes > 0 {
+		fileLimit = maxFileBytes
+	}
+
+	shareLimit := int64(-1)
+	if maxTotalBytes > 0 {
+		sizes := make([]int64, len(files))
+		for i := range files {
+			sizes[i] = int64(len(files[i].Content))
+			if !binary[i] && fileLimit >= 0 && sizes[i] > fileLimit {
+				sizes[i] = fileLimit
+			}
+		}
+		shareLimit = fairShare(sizes, maxTotalBytes)
+	}
+
+	maxBytes := fileLimit
+	if shareLimit >= 0 && (maxBytes < 0 || shareLimit < maxBytes) {
+		maxBytes = shareLimit
+	}
+
+	for i := range files {
+		if binary[i] {
+			continue
+		}
+
+		content, kept, cut := truncateContent(files[i].Content, maxBytes)
+		if !cut {
+			continue
+		}
+		truncated = append(truncated, TruncatedFile{
+			Path: files[i].Path,
+			Size: int64(len(files[i].Content)),
+			Kept: int64(kept),
+		})
+		files[i].Content = content
+	}
+
+	return truncated
+}
+
+// largestFiles returns the files contributing the most bytes to the bundle.
+func largestFiles(files []BundleFile, n int) []FileContribution {
+	contributions := make([]FileContribution, 0, len(files))
+	for _, file := range files {
+		contributions = append(contributions, FileContribution{
+			Path:  file.Path,
+			Bytes: int64(len(file.Content)),
+		})
+	}
+
+	sort.SliceStable(contributions, func(i, j int) bool {
+		return contributions[i].Bytes > contributions[j].Bytes
+	})
+
+	if len(contributions) > n {
+		contributions = contributions[:n]
+	}
+	return contributions
+}
@@ -0,0 +1,89 @@
+package utils_common
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func Test_truncateContent(t *testing.T) {
+	var lines []string
+	for i := 1; i <= 100; i++ {
+		lines = append(lines, fmt.Sprintf("line %03d", i))
+	}
+	content := strings.Join(lines, "\n") + "\n"
+
+	truncated, kept, cut := truncateContent(content, 40)
+
+	assert.True(t, cut)
+	assert.Equal(t, "line 001\nline 002\n[... 96 lines elided ...]\nline 099\nline 100\n", truncated)
+	assert.Equal(t, 36, kept)
+}
+
+func Test_truncateContent_Under_Limit(t *testing.T) {
+	truncated, kept, cut := truncateContent("short\n", 40)
+
+	assert.False(t, cut)
+	assert.Equal(t, "short\n", truncated)
+	assert.Equal(t, 6, kept)
+}
+
+func Test_truncateContent_Long_Line(t *testing.T) {
+	content := strings.Repeat("a", 5000) + strings.Repeat("é", 10)
+
+	truncated, kept, cut := truncateContent(content, 11)
+
+	assert.True(t, cut)
+	assert.Equal(t, "aaaaa\n[... 5,011 bytes elided ...]\néé", truncated)
+	assert.Equal(t, 9, kept)
+}
+
+func Test_truncateContent_Zero_Budget(t *testing.T) {
+	truncated, _, cut := truncateContent("a\nb\nc\n", 0)
+
+	assert.True(t, cut)
+	assert.Equal(t, "[... 3 lines elided ...]\n", truncated)
+}
+
+func Test_formatCount(t *testing.T) {
+	assert.Equal(t, "0", formatCount(0))
+	assert.Equal(t, "999", formatCount(999))
+	assert.Equal(t, "4,210", formatCount(4210))
+	assert.Equal(t, "1,234,567", formatCount(1234567))
+	assert.Equal(t, "-4,210", formatCount(-4210))
+}
+
+func Test_fairShare(t *testing.T) {
+	assert.Equal(t, int64(-1), fairShare([]int64{10, 20, 30}, 60), "fits")
+	assert.Equal(t, int64(25), fairShare([]int64{10, 100, 30}, 60), "small files kept whole")
+	assert.Equal(t, int64(20), fairShare([]int64{100, 100, 100}, 60), "even split")
+}
+
+func Test_applySizeLimits(t *testing.T) {
+	files := []BundleFile{
+		{Path: "small.go", Content: strings.Repeat("s\n", 5)},
+		{Path: "big.json", Content: strings.Repeat("b\n", 500)},
+		{Path: "image.png", Content: "[binary, 34 KB, image/png]"},
+	}
+
+	truncated := applySizeLimits(files, []bool{false, false, true}, 100, 0)
+
+	assert.Equal(t, []TruncatedFile{{Path: "big.json", Size: 1000, Kept: 100}}, truncated)
+	assert.Equal(t, strings.Repeat("s\n", 5), files[0].Content)
+	assert.Contains(t, files[1].Content, "[... 450 lines elided ...]")
+	assert.Equal(t, "[binary, 34 KB, image/png]", files[2].Content)
+}
+
+func Test_largestFiles(t *testing.T) {
+	files := []BundleFile{
+		{Path: "a", Content: "12"},
+		{Path: "b", Content: "1234"},
+		{Path: "c", Content: "123"},
+	}
+
+	assert.Equal(t, []FileContribution{
+		{Path: "b", Bytes: 4},
+		{Path: "c", Bytes: 3},
+	}, largestFiles(files, 2))
+}
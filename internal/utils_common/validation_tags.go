@@ -53,6 +53,16 @@ var customValidations = []CustomValidation{
 			String: "string exceeds the max number of characters",
 		},
 	},
+	{
+		Name: "oneof",
+		Logic: nullableFunc{
+			Valid: false,
+		},
+		Response: null.String{
+			Valid:  true,
+			String: "has an unsupported value '__VAL__'",
+		},
+	},
 	{
 		Name: "is_file_name",
 		Logic: nullableFunc{